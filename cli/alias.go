@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"rabbit-todo/cli/param"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Alias is a user-defined command name that expands to a full invocation, like git aliases.
+// The Expansion is split into tokens by Tokenize. The placeholders $1, $2, ... in it are replaced
+// with the arguments given after the alias name, "$$" is replaced with a literal "$",
+// and the arguments that are not referred to by a placeholder are appended to the expansion.
+type Alias struct {
+	Name      string
+	Expansion string
+}
+
+var aliasPlaceholder = regexp.MustCompile(`\$(\$|[0-9]+)`)
+
+// expand returns the tokens of the alias with the placeholders replaced by params.
+// It returns an error if the expansion cannot be tokenized or refers to a missing parameter.
+func (a *Alias) expand(params []string) ([]string, error) {
+	tokens, err := Tokenize(a.Expansion)
+	if err != nil {
+		return nil, Errorf("invalid alias %s: %w", a.Name, err)
+	}
+
+	used := make(map[int]bool)
+	var expandErr error
+	expanded := make([]string, 0, len(tokens)+len(params))
+	for _, token := range tokens {
+		token = aliasPlaceholder.ReplaceAllStringFunc(token, func(placeholder string) string {
+			if placeholder == "$$" {
+				return "$"
+			}
+			n, _ := strconv.Atoi(strings.TrimPrefix(placeholder, "$"))
+			if n < 1 || n > len(params) {
+				if expandErr == nil {
+					expandErr = Errorf("alias %s requires argument %s", a.Name, placeholder)
+				}
+				return ""
+			}
+			used[n] = true
+			return params[n-1]
+		})
+		expanded = append(expanded, token)
+	}
+	if expandErr != nil {
+		return nil, expandErr
+	}
+
+	for i, p := range params {
+		if !used[i+1] {
+			expanded = append(expanded, p)
+		}
+	}
+	return expanded, nil
+}
+
+// AddAlias defines an alias that expands to the given invocation.
+// It returns an error if the name is not a single word, the expansion is empty or cannot be tokenized,
+// the name is already an alias, or the alias would shadow a command.
+func (p *Parser) AddAlias(name string, expansion string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n=") || !isArgument(name) {
+		return Errorf("invalid alias name %q", name)
+	}
+	tokens, err := Tokenize(expansion)
+	if err != nil {
+		return Errorf("invalid alias %s: %w", name, err)
+	}
+	if len(tokens) == 0 {
+		return Errorf("alias %s must not be empty", name)
+	}
+	if p.isCommandName(name) {
+		return Errorf("alias %s shadows command %s", name, name)
+	}
+	if p.findAlias(name) != nil {
+		return Errorf("duplicate alias name %s", name)
+	}
+	p.aliases = append(p.aliases, Alias{Name: name, Expansion: expansion})
+	return nil
+}
+
+// RemoveAlias removes the alias with the given name.
+// It returns an error if there is no such alias.
+func (p *Parser) RemoveAlias(name string) error {
+	for i, alias := range p.aliases {
+		if alias.Name == name {
+			p.aliases = append(p.aliases[:i], p.aliases[i+1:]...)
+			return nil
+		}
+	}
+	return Errorf("unknown alias %s", name)
+}
+
+// Aliases returns the aliases of the parser sorted by name.
+func (p *Parser) Aliases() []Alias {
+	aliases := append([]Alias{}, p.aliases...)
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases
+}
+
+// LoadAliases reads an alias file and adds its aliases to the parser.
+// A missing file is treated as an empty one.
+func (p *Parser) LoadAliases(path string) error {
+	aliases, err := ReadAliasFile(path)
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := p.AddAlias(alias.Name, alias.Expansion); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// findAlias returns the alias with the given name, or nil if there is none.
+func (p *Parser) findAlias(name string) *Alias {
+	for i := range p.aliases {
+		if p.aliases[i].Name == name {
+			return &p.aliases[i]
+		}
+	}
+	return nil
+}
+
+// isCommandName reports whether name is the name, or the first word of the name, of a command.
+func (p *Parser) isCommandName(name string) bool {
+	for _, command := range p.commands {
+		if words := strings.Fields(command.Name); len(words) > 0 && words[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAliases replaces the command name in args with the expansion of the alias of that name,
+// repeatedly, so that aliases may refer to other aliases.
+// Global options before the command name are kept as they are.
+// It returns an error if an alias refers back to itself.
+func (p *Parser) expandAliases(args []string) ([]string, error) {
+	var chain []string
+	for {
+		idx := p.commandIndex(args)
+		if idx < 0 || p.isCommandName(args[idx]) {
+			return args, nil
+		}
+		alias := p.findAlias(args[idx])
+		if alias == nil {
+			return args, nil
+		}
+		for _, name := range chain {
+			if name == alias.Name {
+				return nil, Errorf("recursive alias %s -> %s", strings.Join(chain, " -> "), alias.Name)
+			}
+		}
+		chain = append(chain, alias.Name)
+
+		expanded, err := alias.expand(args[idx+1:])
+		if err != nil {
+			return nil, err
+		}
+		args = append(append(make([]string, 0, idx+len(expanded)), args[:idx]...), expanded...)
+	}
+}
+
+// commandIndex returns the index of the command name in args, skipping the global options before it,
+// or -1 if there is no command name.
+func (p *Parser) commandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		names := expandShortOption(p.options, args[i])
+		option := p.findOption(names[len(names)-1])
+		switch {
+		case option != nil && !option.IsFlag:
+			i++
+		case option != nil:
+		case isArgument(args[i]):
+			return i
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// ReadAliasFile reads aliases from a git-style config file in which each line has the form
+// "name = expansion". Empty lines and lines starting with '#' are ignored.
+// A missing file is treated as an empty one.
+func ReadAliasFile(path string) ([]Alias, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var aliases []Alias
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, expansion, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, Errorf("%s:%d: invalid alias definition %q", path, lineNumber, line)
+		}
+		aliases = append(aliases, Alias{Name: strings.TrimSpace(name), Expansion: strings.TrimSpace(expansion)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// WriteAliasFile writes the aliases to a file in the format read by ReadAliasFile.
+func WriteAliasFile(path string, aliases []Alias) error {
+	var builder strings.Builder
+	for _, alias := range aliases {
+		builder.WriteString(fmt.Sprintf("%s = %s\n", alias.Name, alias.Expansion))
+	}
+	return os.WriteFile(path, []byte(builder.String()), 0o644)
+}
+
+// NewAliasCommands constructs the "alias list", "alias add" and "alias remove" commands,
+// which manage the aliases of the parser and save them to the alias file at path.
+func NewAliasCommands(p *Parser, path string) []Command {
+	list := NewStreamCommand("alias list", func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		for _, alias := range p.Aliases() {
+			if _, err := fmt.Fprintf(stdio.Out, "%s = %s\n", alias.Name, alias.Expansion); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	add := NewCommand("alias add", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		name := args["name"].StringVal
+		if err := p.AddAlias(name, args["expansion"].StringVal); err != nil {
+			return "", err
+		}
+		if err := WriteAliasFile(path, p.Aliases()); err != nil {
+			return "", err
+		}
+		return Sprintf("added alias %s", name), nil
+	})
+	nameArg, _ := param.NewArgument("name", param.STRING)
+	expansionArg, _ := param.NewArgument("expansion", param.STRING)
+	_ = add.AddArgument(nameArg)
+	_ = add.AddArgument(expansionArg)
+
+	remove := NewCommand("alias remove", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		name := args["name"].StringVal
+		if err := p.RemoveAlias(name); err != nil {
+			return "", err
+		}
+		if err := WriteAliasFile(path, p.Aliases()); err != nil {
+			return "", err
+		}
+		return Sprintf("removed alias %s", name), nil
+	})
+	_ = remove.AddArgument(nameArg)
+
+	return []Command{list, add, remove}
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newAliasTestParser constructs a parser with a "list" command that echoes its options,
+// a "--no-color" global flag and the given aliases.
+func newAliasTestParser(t *testing.T, aliases map[string]string) *Parser {
+	t.Helper()
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("list project:%s due:%s sort:%s no-color:%t",
+			args["project"].StringVal, opts["due"].StringVal, opts["sort"].StringVal, opts["no-color"].BoolVal), nil
+	}
+	projectArg, _ := param.NewArgument("project", param.STRING)
+	dueOption, _ := param.NewOption("--due", param.STRING)
+	sortOption, _ := param.NewOption("--sort", param.STRING)
+	command := NewCommand("list", testAction)
+	_ = command.AddArgument(projectArg)
+	_ = command.AddOption(dueOption)
+	_ = command.AddOption(sortOption)
+	noColorOption, _ := param.NewFlagOption("--no-color")
+
+	parser := NewParser()
+	_ = parser.AddOption(noColorOption)
+	_ = parser.AddCommand(command)
+	for name, expansion := range aliases {
+		if err := parser.AddAlias(name, expansion); err != nil {
+			t.Fatalf("Parser.AddAlias() error = %v", err)
+		}
+	}
+	return parser
+}
+
+func TestParser_Execute_With_Aliases(t *testing.T) {
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	parser := newAliasTestParser(t, map[string]string{
+		"today":   "list inbox --due today",
+		"due":     "list $2 --due $1",
+		"home":    "due today home",
+		"cost":    "list '$$5' --due $3",
+		"loop-a":  "loop-b",
+		"loop-b":  "loop-a",
+		"unknown": "missing-command",
+	})
+
+	tests := []testCase{
+		{
+			testName: "Ok-PassThroughArguments",
+			args:     []string{"today", "--sort", "priority"},
+			want:     "list project:inbox due:today sort:priority no-color:false",
+		},
+		{
+			testName: "Ok-GlobalOptionsAroundAlias",
+			args:     []string{"--no-color", "today", "--sort", "priority"},
+			want:     "list project:inbox due:today sort:priority no-color:true",
+		},
+		{
+			testName: "Ok-PositionalSubstitution",
+			args:     []string{"due", "tomorrow", "work", "--sort", "title"},
+			want:     "list project:work due:tomorrow sort:title no-color:false",
+		},
+		{
+			testName: "Ok-AliasOfAlias",
+			args:     []string{"home", "--sort", "title"},
+			want:     "list project:home due:today sort:title no-color:false",
+		},
+		{
+			testName:   "Error-MissingPositionalArgument",
+			args:       []string{"cost", "a", "b"},
+			wantErr:    true,
+			wantErrStr: "alias cost requires argument $3",
+		},
+		{
+			testName:   "Error-RecursiveAlias",
+			args:       []string{"loop-a"},
+			wantErr:    true,
+			wantErrStr: "recursive alias loop-a -> loop-b -> loop-a",
+		},
+		{
+			testName:   "Error-AliasToUnknownCommand",
+			args:       []string{"unknown"},
+			wantErr:    true,
+			wantErrStr: "unknown command missing-command",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := parser.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAlias_expand(t *testing.T) {
+	alias := Alias{Name: "cost", Expansion: "list '$$5' --due $1"}
+	got, err := alias.expand([]string{"today", "work"})
+	if err != nil {
+		t.Fatalf("Alias.expand() error = %v", err)
+	}
+	want := []string{"list", "$5", "--due", "today", "work"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Alias.expand() = %q, want %q", got, want)
+	}
+}
+
+func TestParser_AddAlias(t *testing.T) {
+	type testCase struct {
+		testName   string
+		name       string
+		expansion  string
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName:  "Ok-AddAlias",
+			name:      "week",
+			expansion: "list --due week",
+		},
+		{
+			testName:   "Error-ShadowsCommand",
+			name:       "list",
+			expansion:  "list --due today",
+			wantErr:    true,
+			wantErrStr: "alias list shadows command list",
+		},
+		{
+			testName:   "Error-DuplicateAlias",
+			name:       "today",
+			expansion:  "list",
+			wantErr:    true,
+			wantErrStr: "duplicate alias name today",
+		},
+		{
+			testName:   "Error-InvalidName",
+			name:       "--today",
+			expansion:  "list",
+			wantErr:    true,
+			wantErrStr: "invalid alias name \"--today\"",
+		},
+		{
+			testName:   "Error-EmptyExpansion",
+			name:       "nothing",
+			expansion:  "  ",
+			wantErr:    true,
+			wantErrStr: "alias nothing must not be empty",
+		},
+		{
+			testName:   "Error-InvalidExpansion",
+			name:       "broken",
+			expansion:  "list 'oops",
+			wantErr:    true,
+			wantErrStr: "invalid alias broken: unterminated single quote",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			parser := newAliasTestParser(t, map[string]string{"today": "list --due today"})
+			err := parser.AddAlias(tc.name, tc.expansion)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.AddAlias() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr && err.Error() != tc.wantErrStr {
+				t.Errorf("Parser.AddAlias() error = %q, wantErrStr %q", err, tc.wantErrStr)
+			}
+		})
+	}
+}
+
+func TestNewAliasCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases")
+	if err := os.WriteFile(path, []byte("# aliases\ntoday = list --due today\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := newAliasTestParser(t, nil)
+	if err := parser.LoadAliases(path); err != nil {
+		t.Fatalf("Parser.LoadAliases() error = %v", err)
+	}
+	for _, command := range NewAliasCommands(parser, path) {
+		if err := parser.AddCommand(command); err != nil {
+			t.Fatalf("Parser.AddCommand() error = %v", err)
+		}
+	}
+	var stdout bytes.Buffer
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &stdout})
+
+	steps := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"alias", "add", "week", "list --due week"}, want: "added alias week"},
+		{args: []string{"alias", "remove", "today"}, want: "removed alias today"},
+		{args: []string{"alias", "list"}, want: ""},
+	}
+	for _, step := range steps {
+		got, err := parser.Execute(step.args)
+		if err != nil {
+			t.Fatalf("Parser.Execute(%q) error = %v", step.args, err)
+		}
+		if got != step.want {
+			t.Errorf("Parser.Execute(%q) = %q, want %q", step.args, got, step.want)
+		}
+	}
+	if want := "week = list --due week\n"; stdout.String() != want {
+		t.Errorf("alias list output = %q, want %q", stdout.String(), want)
+	}
+
+	aliases, err := ReadAliasFile(path)
+	if err != nil {
+		t.Fatalf("ReadAliasFile() error = %v", err)
+	}
+	want := []Alias{{Name: "week", Expansion: "list --due week"}}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("ReadAliasFile() = %v, want %v", aliases, want)
+	}
+
+	if _, err := parser.Execute([]string{"alias", "add", "alias", "list"}); err == nil ||
+		err.Error() != "alias alias shadows command alias" {
+		t.Errorf("Parser.Execute() error = %v, want shadowing error", err)
+	}
+}
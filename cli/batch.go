@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"rabbit-todo/cli/param"
+)
+
+const (
+	batchCommandName    = "batch"
+	continueOnErrorName = "--continue-on-error"
+	batchStdinFileName  = "-"
+)
+
+// Transaction is implemented by applications to make the changes of a batch run atomic.
+// It is typically backed by a task store that is loaded once for the whole batch.
+type Transaction interface {
+	Commit() error
+	Rollback() error
+}
+
+// BeginFunc starts the Transaction that the commands of a batch run are executed in.
+type BeginFunc func() (Transaction, error)
+
+// NewBatchCommand constructs the "batch" command, which executes the commands in a file,
+// or in the standard input if the file is "-", with the parser, one command per line.
+// Lines are split with Tokenize, and empty lines and comments are skipped.
+// The commands run in a single Transaction started by begin, unless begin is nil.
+// By default the batch stops at the first failing line and rolls back the transaction.
+// With --continue-on-error every line is executed, failures are reported with their line numbers,
+// and the changes of the successful lines are committed.
+// The batch itself is never paged, and neither are the commands it executes.
+func NewBatchCommand(p *Parser, begin BeginFunc) Command {
+	command := NewStreamCommand(batchCommandName, func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		return runBatch(p, stdio, args["file"].StringVal, opts["continue-on-error"].BoolVal, begin)
+	})
+	fileArg, _ := param.NewArgument("file", param.STRING)
+	fileArg.Description = "File with one command per line, or - for the standard input"
+	continueOption, _ := param.NewFlagOption(continueOnErrorName)
+	_ = command.AddArgument(fileArg)
+	_ = command.AddOption(continueOption)
+	command.noPager = true
+	return command
+}
+
+// runBatch executes the commands of the batch file in a transaction.
+// The output of each command is written to stdio.Out and errors are written to stdio.Err with their line number.
+func runBatch(p *Parser, stdio *IO, path string, continueOnError bool, begin BeginFunc) error {
+	reader := stdio.In
+	if path != batchStdinFileName {
+		file, err := os.Open(path)
+		if err != nil {
+			return Errorf("cannot open batch file %s: %w", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var tx Transaction
+	if begin != nil {
+		var err error
+		if tx, err = begin(); err != nil {
+			return err
+		}
+	}
+
+	failed, total, err := executeBatchLines(p, stdio, reader, continueOnError)
+	if err != nil {
+		if tx != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return Errorf("%w (rollback failed: %v)", err, rollbackErr)
+			}
+		}
+		return err
+	}
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return Errorf("%d of %d commands failed", failed, total)
+	}
+	return nil
+}
+
+// executeBatchLines executes each command line read from reader with stdio.
+// It returns the number of failed and executed commands, and an error that aborts the batch:
+// the first failure unless continueOnError is set, or a failure to read or write.
+func executeBatchLines(p *Parser, stdio *IO, reader io.Reader, continueOnError bool) (int, int, error) {
+	failed, total := 0, 0
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		tokens, err := Tokenize(scanner.Text())
+		if err == nil && len(tokens) == 0 {
+			continue
+		}
+		total++
+		if err == nil && isBatchCommand(p.Parse(tokens).Command) {
+			err = Errorf("batch cannot be nested")
+		}
+
+		var output string
+		if err == nil {
+			output, err = p.execute(tokens, stdio)
+		}
+		if err != nil {
+			if !continueOnError {
+				return failed, total, Errorf("line %d: %w", lineNumber, err)
+			}
+			failed++
+			if _, err := fmt.Fprintln(stdio.Err, Sprintf("line %d: %v", lineNumber, err)); err != nil {
+				return failed, total, err
+			}
+			continue
+		}
+		if output != "" {
+			if _, err := fmt.Fprintln(stdio.Out, output); err != nil {
+				return failed, total, err
+			}
+		}
+	}
+	return failed, total, scanner.Err()
+}
+
+// isBatchCommand reports whether the command is the batch command,
+// which a command line in a batch must not resolve to, even through an alias.
+func isBatchCommand(command *Command) bool {
+	return command != nil && command.Name == batchCommandName
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testTransaction struct {
+	events *[]string
+}
+
+func (tx testTransaction) Commit() error {
+	*tx.events = append(*tx.events, "commit")
+	return nil
+}
+
+func (tx testTransaction) Rollback() error {
+	*tx.events = append(*tx.events, "rollback")
+	return nil
+}
+
+func TestNewBatchCommand(t *testing.T) {
+	batchFile := filepath.Join(t.TempDir(), "tasks.txt")
+	content := "# morning\nadd 'Buy milk'\n\nadd\nadd \"Call mom\"\n"
+	if err := os.WriteFile(batchFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		stdin      string
+		wantStdout string
+		wantStderr string
+		wantEvents []string
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName:   "Ok-AllLinesSucceed",
+			args:       []string{"batch", "-"},
+			stdin:      "add one\nadd two\n",
+			wantStdout: "added one\nadded two\n",
+			wantEvents: []string{"begin", "add one", "add two", "commit"},
+		},
+		{
+			testName:   "Error-StopAtFirstFailure",
+			args:       []string{"batch", batchFile},
+			wantStdout: "added Buy milk\n",
+			wantEvents: []string{"begin", "add Buy milk", "rollback"},
+			wantErr:    true,
+			wantErrStr: "line 4: not enough arguments: actual 0, expected 1",
+		},
+		{
+			testName:   "Error-ContinueOnError",
+			args:       []string{"batch", batchFile, "--continue-on-error"},
+			wantStdout: "added Buy milk\nadded Call mom\n",
+			wantStderr: "line 4: not enough arguments: actual 0, expected 1\n",
+			wantEvents: []string{"begin", "add Buy milk", "add Call mom", "commit"},
+			wantErr:    true,
+			wantErrStr: "1 of 3 commands failed",
+		},
+		{
+			testName:   "Error-NestedBatch",
+			args:       []string{"batch", "-", "--continue-on-error"},
+			stdin:      "batch -\nadd 'unterminated\n",
+			wantStderr: "line 1: batch cannot be nested\nline 2: unterminated single quote\n",
+			wantEvents: []string{"begin", "commit"},
+			wantErr:    true,
+			wantErrStr: "2 of 2 commands failed",
+		},
+		{
+			testName:   "Error-NestedBatchAfterGlobalOption",
+			args:       []string{"batch", "-"},
+			stdin:      "--verbose batch -\n",
+			wantEvents: []string{"begin", "rollback"},
+			wantErr:    true,
+			wantErrStr: "line 1: batch cannot be nested",
+		},
+		{
+			testName:   "Error-NestedBatchThroughAlias",
+			args:       []string{"batch", "-"},
+			stdin:      "again\n",
+			wantEvents: []string{"begin", "rollback"},
+			wantErr:    true,
+			wantErrStr: "line 1: batch cannot be nested",
+		},
+		{
+			testName:   "Error-MissingFile",
+			args:       []string{"batch", filepath.Join(filepath.Dir(batchFile), "missing.txt")},
+			wantErr:    true,
+			wantErrStr: fmt.Sprintf("cannot open batch file %s: open %[1]s: no such file or directory", filepath.Join(filepath.Dir(batchFile), "missing.txt")),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var events []string
+			testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+				events = append(events, "add "+args["title"].StringVal)
+				return "added " + args["title"].StringVal, nil
+			}
+			titleArg, _ := param.NewArgument("title", param.STRING)
+			add := NewCommand("add", testAction)
+			_ = add.AddArgument(titleArg)
+
+			var stdout, stderr bytes.Buffer
+			parser := NewParser()
+			parser.SetIO(&IO{In: strings.NewReader(tc.stdin), Out: &stdout, Err: &stderr})
+			verboseOption, _ := param.NewFlagOption("--verbose")
+			_ = parser.AddOption(verboseOption)
+			_ = parser.AddCommand(add)
+			_ = parser.AddCommand(NewBatchCommand(parser, func() (Transaction, error) {
+				events = append(events, "begin")
+				return testTransaction{events: &events}, nil
+			}))
+			_ = parser.AddAlias("again", "batch -")
+
+			_, err := parser.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr && err.Error() != tc.wantErrStr {
+				t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+			}
+			if stdout.String() != tc.wantStdout {
+				t.Errorf("Parser.Execute() stdout = %q, want %q", stdout.String(), tc.wantStdout)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("Parser.Execute() stderr = %q, want %q", stderr.String(), tc.wantStderr)
+			}
+			if !reflect.DeepEqual(events, tc.wantEvents) {
+				t.Errorf("transaction events = %q, want %q", events, tc.wantEvents)
+			}
+		})
+	}
+}
+
+func TestNewBatchCommand_IO(t *testing.T) {
+	var parserOut, batchOut bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &parserOut, Err: &bytes.Buffer{}})
+	_ = parser.AddCommand(NewCommand("returned", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "returned", nil
+	}))
+	_ = parser.AddCommand(NewStreamCommand("streamed", func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		_, err := fmt.Fprintln(stdio.Out, "streamed")
+		return err
+	}))
+	batch := NewBatchCommand(parser, nil)
+
+	stdio := &IO{In: strings.NewReader("returned\nstreamed\n"), Out: &batchOut, Err: &bytes.Buffer{}}
+	if _, err := batch.ExecuteWithIO(stdio, []string{"-"}); err != nil {
+		t.Fatalf("Command.ExecuteWithIO() error = %v", err)
+	}
+	if want := "returned\nstreamed\n"; batchOut.String() != want {
+		t.Errorf("batch stdout = %q, want %q", batchOut.String(), want)
+	}
+	if parserOut.String() != "" {
+		t.Errorf("parser stdout = %q, want empty", parserOut.String())
+	}
+}
@@ -0,0 +1,181 @@
+// Package clitest runs whole command lines through a cli.Parser in tests
+// and compares their output and exit code against golden files.
+//
+// Golden files live in the testdata directory of the package under test and are named after the test.
+// Run the tests with the -update flag to regenerate them from the actual results.
+package clitest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DataDirPlaceholder replaces the path of the temporary data directory in the captured output,
+// so that golden files do not depend on where the test runs.
+const DataDirPlaceholder = "$DATA_DIR"
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Case is one command line run by a test.
+// Env holds environment variables that are set for the duration of the run,
+// and Now is the time the fake clock of the run starts at.
+type Case struct {
+	Name  string
+	Args  []string
+	Stdin string
+	Env   map[string]string
+	Now   time.Time
+}
+
+// Env is the environment a parser is constructed in for a single run.
+// DataDir is an empty temporary directory that is removed after the test,
+// and Clock is a fake clock that the application should read the current time from.
+type Env struct {
+	DataDir string
+	Clock   *Clock
+}
+
+// Setup constructs the parser under test in the given environment.
+// It returns a pointer to the parser, so that commands which refer back to it, such as the batch command,
+// run with the IO that captures the output of the run.
+type Setup func(t *testing.T, env *Env) *cli.Parser
+
+// Result is the captured outcome of a run.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Clock is a fake clock whose time only changes when it is set or advanced.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock constructs a fake clock that starts at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Run constructs a parser with setup and executes the arguments of the case with it,
+// capturing its output and exit code. The output is not styled unless the case sets CLICOLOR_FORCE,
+// and the messages are in DefaultLocale unless the case sets LC_ALL, LC_MESSAGES or LANG.
+// The output returned by the command is written to stdout followed by a newline.
+// An error is written to stderr and results in the exit code 1,
+// or the code of the plugin for a cli.ExitError.
+func Run(t *testing.T, setup Setup, c Case) Result {
+	t.Helper()
+	t.Setenv("CLICOLOR_FORCE", "")
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(key, "")
+	}
+	for key, value := range c.Env {
+		t.Setenv(key, value)
+	}
+	env := &Env{
+		DataDir: t.TempDir(),
+		Clock:   NewClock(c.Now),
+	}
+
+	var stdout, stderr bytes.Buffer
+	parser := setup(t, env)
+	parser.SetIO(&cli.IO{In: strings.NewReader(c.Stdin), Out: &stdout, Err: &stderr})
+
+	exitCode := 0
+	output, err := parser.Execute(c.Args)
+	if output != "" {
+		stdout.WriteString(output + "\n")
+	}
+	var exitErr *cli.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.Code
+	case err != nil:
+		fmt.Fprintf(&stderr, "error: %v\n", err)
+		exitCode = 1
+	}
+
+	return Result{
+		Stdout:   strings.ReplaceAll(stdout.String(), env.DataDir, DataDirPlaceholder),
+		Stderr:   strings.ReplaceAll(stderr.String(), env.DataDir, DataDirPlaceholder),
+		ExitCode: exitCode,
+	}
+}
+
+// String formats the result as it is stored in a golden file.
+func (r Result) String() string {
+	return fmt.Sprintf("-- stdout --\n%s-- stderr --\n%s-- exit code --\n%d\n", r.Stdout, r.Stderr, r.ExitCode)
+}
+
+// RunGolden runs each case in a subtest named after it and compares the result
+// against the golden file of the subtest.
+func RunGolden(t *testing.T, setup Setup, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			AssertGolden(t, Run(t, setup, c))
+		})
+	}
+}
+
+// AssertGolden compares the result against the golden file of the test, testdata/<test name>.golden.
+// With the -update flag, it writes the result to the golden file instead.
+func AssertGolden(t *testing.T, result Result) {
+	t.Helper()
+	path := GoldenPath(t)
+	got := result.String()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("cannot update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read golden file %s (run the test with -update to create it): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("result does not match golden file %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// GoldenPath returns the path of the golden file of the test.
+// The slashes separating subtest names become directory separators.
+func GoldenPath(t *testing.T) string {
+	return filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+}
@@ -0,0 +1,121 @@
+package clitest
+
+import (
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli"
+	"rabbit-todo/cli/param"
+	"testing"
+	"time"
+)
+
+func setupTodo(t *testing.T, env *Env) *cli.Parser {
+	add := cli.NewStreamCommand("add", func(stdio *cli.IO, args map[string]param.Value, opts map[string]param.Value) error {
+		path := filepath.Join(env.DataDir, "tasks.txt")
+		line := env.Clock.Now().Format(time.DateOnly) + " " + args["title"].StringVal + "\n"
+		if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+			return err
+		}
+		_, err := stdio.Out.Write([]byte("added to " + path + "\n"))
+		return err
+	})
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	_ = add.AddArgument(titleArg)
+
+	greet := cli.NewCommand("greet", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "hello " + os.Getenv("TODO_USER"), nil
+	})
+
+	parser := cli.NewParser()
+	if err := parser.AddCommand(add); err != nil {
+		t.Fatalf("Parser.AddCommand() error = %v", err)
+	}
+	if err := parser.AddCommand(greet); err != nil {
+		t.Fatalf("Parser.AddCommand() error = %v", err)
+	}
+	return parser
+}
+
+func TestRunGolden(t *testing.T) {
+	RunGolden(t, setupTodo, []Case{
+		{
+			Name: "Ok-Add",
+			Args: []string{"add", "milk"},
+			Now:  time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name: "Ok-Env",
+			Args: []string{"greet"},
+			Env:  map[string]string{"TODO_USER": "alice"},
+		},
+		{
+			Name: "Error-UnknownCommand",
+			Args: []string{"remove"},
+		},
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Ok-FakeClockAndDataDir", func(t *testing.T) {
+		var dataDir string
+		var now time.Time
+		setup := func(t *testing.T, env *Env) *cli.Parser {
+			dataDir = env.DataDir
+			env.Clock.Advance(time.Hour)
+			now = env.Clock.Now()
+			return setupTodo(t, env)
+		}
+		start := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+		got := Run(t, setup, Case{Args: []string{"add", "milk"}, Now: start})
+
+		want := Result{Stdout: "added to $DATA_DIR/tasks.txt\n"}
+		if got != want {
+			t.Errorf("Run() = %+v, want %+v", got, want)
+		}
+		if !now.Equal(start.Add(time.Hour)) {
+			t.Errorf("Clock.Now() = %v, want %v", now, start.Add(time.Hour))
+		}
+		content, err := os.ReadFile(filepath.Join(dataDir, "tasks.txt"))
+		if err != nil {
+			t.Fatalf("cannot read data file: %v", err)
+		}
+		if string(content) != "2026-10-20 milk\n" {
+			t.Errorf("data file = %q, want %q", content, "2026-10-20 milk\n")
+		}
+	})
+
+	t.Run("Ok-CommandReferringToParser", func(t *testing.T) {
+		setup := func(t *testing.T, env *Env) *cli.Parser {
+			parser := setupTodo(t, env)
+			if err := parser.AddCommand(cli.NewBatchCommand(parser, nil)); err != nil {
+				t.Fatalf("Parser.AddCommand() error = %v", err)
+			}
+			return parser
+		}
+		got := Run(t, setup, Case{Args: []string{"batch", "-"}, Stdin: "add milk\n"})
+
+		want := Result{Stdout: "added to $DATA_DIR/tasks.txt\n"}
+		if got != want {
+			t.Errorf("Run() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Ok-LocaleOfEnvironmentIgnored", func(t *testing.T) {
+		t.Setenv("LANG", "ja_JP.UTF-8")
+		got := Run(t, setupTodo, Case{Args: []string{"remove"}})
+
+		want := Result{Stderr: "error: unknown command remove\n", ExitCode: 1}
+		if got != want {
+			t.Errorf("Run() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Ok-LocaleOfCase", func(t *testing.T) {
+		got := Run(t, setupTodo, Case{Args: []string{"remove"}, Env: map[string]string{"LANG": "ja_JP.UTF-8"}})
+
+		want := Result{Stderr: "error: 不明なコマンド remove\n", ExitCode: 1}
+		if got != want {
+			t.Errorf("Run() = %+v, want %+v", got, want)
+		}
+	})
+}
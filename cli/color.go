@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"rabbit-todo/cli/param"
+	"rabbit-todo/cli/style"
+)
+
+const colorOptionName = "--color"
+
+// EnableColorOption registers the global option --color, which selects when the output of a command is styled:
+// auto, always or never. Like any option, its value can also come from the config of the parser.
+func (p *Parser) EnableColorOption() error {
+	colorOption, err := param.NewOption(colorOptionName, param.STRING)
+	if err != nil {
+		return err
+	}
+	colorOption.Description = "when to color the output: auto, always or never"
+	colorOption.AddValidators(param.OneOf(style.Modes...))
+	colorOption.Complete = func(partial string, parsed map[string]param.Value) []param.Candidate {
+		candidates := make([]param.Candidate, 0, len(style.Modes))
+		for _, mode := range style.Modes {
+			candidates = append(candidates, param.Candidate{Value: mode})
+		}
+		return candidates
+	}
+	if err := p.AddOption(colorOption); err != nil {
+		return err
+	}
+	p.colorOption = true
+	return nil
+}
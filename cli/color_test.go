@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"rabbit-todo/cli/param"
+	"rabbit-todo/cli/style"
+	"strings"
+	"testing"
+)
+
+func TestParser_Execute_With_ColorOption(t *testing.T) {
+	overdue := NewStreamCommand("overdue", func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		_, err := stdio.Out.Write([]byte(stdio.Style().Render(style.Red, "buy milk") + "\n"))
+		return err
+	})
+
+	type testCase struct {
+		testName      string
+		args          []string
+		config        map[string]string
+		cliColorForce string
+		want          string
+		wantErrStr    string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-AutoNotTerminal",
+			args:     []string{"overdue"},
+			want:     "buy milk\n",
+		},
+		{
+			testName: "Ok-Always",
+			args:     []string{"--color", "always", "overdue"},
+			want:     "\x1b[31mbuy milk\x1b[0m\n",
+		},
+		{
+			testName:      "Ok-NeverOverCliColorForce",
+			args:          []string{"overdue", "--color", "never"},
+			cliColorForce: "1",
+			want:          "buy milk\n",
+		},
+		{
+			testName:      "Ok-CliColorForce",
+			args:          []string{"overdue"},
+			cliColorForce: "1",
+			want:          "\x1b[31mbuy milk\x1b[0m\n",
+		},
+		{
+			testName: "Ok-Config",
+			args:     []string{"overdue"},
+			config:   map[string]string{"color": "always"},
+			want:     "\x1b[31mbuy milk\x1b[0m\n",
+		},
+		{
+			testName:   "Error-InvalidMode",
+			args:       []string{"--color", "sometimes", "overdue"},
+			wantErrStr: "invalid option \"--color\": one-of[auto always never]: sometimes is not one of [auto always never]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("CLICOLOR_FORCE", tc.cliColorForce)
+			var stdout bytes.Buffer
+			parser := NewParser()
+			parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}})
+			if err := parser.EnableColorOption(); err != nil {
+				t.Fatalf("Parser.EnableColorOption() error = %v", err)
+			}
+			_ = parser.AddCommand(overdue)
+			parser.SetConfig(tc.config)
+
+			_, err := parser.Execute(tc.args)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parser.Execute() error = %v", err)
+			}
+			if got := stdout.String(); got != tc.want {
+				t.Errorf("Parser.Execute() stdout = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_Help_Styled(t *testing.T) {
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Color: style.Always})
+	_ = parser.AddCommand(NewCommand("list", nil))
+
+	want := "\x1b[1mCommands:\x1b[0m\n  list"
+	if got := parser.Help(); got != want {
+		t.Errorf("Parser.Help() = %q, want %q", got, want)
+	}
+}
@@ -14,11 +14,22 @@ const (
 // options, the action to execute, and usage information.
 // The struct is used to define commands in a CLI application and provides
 // methods to execute and validate command input.
+// Description is the one-line summary and Category the group, such as "Tasks",
+// that the command is listed with in the help of the parser.
 type Command struct {
-	Name      string
-	arguments []*param.Argument
-	options   []*param.Option
-	action    Action
+	Name         string
+	Description  string
+	Category     string
+	arguments    []*param.Argument
+	options      []*param.Option
+	action       Action
+	streamAction StreamAction
+	middlewares  []Middleware
+	groups       []optionGroup
+	hidden       bool
+	deprecated   *param.Deprecation
+	rawAction    func(stdio *IO, params []string) error
+	noPager      bool
 }
 
 // Action defines the function signature for actions that commands execute.
@@ -27,6 +38,11 @@ type Command struct {
 // a message to the user, and an error if the execution fails.
 type Action func(args map[string]param.Value, opts map[string]param.Value) (string, error)
 
+// StreamAction defines the function signature for actions that stream their input and output.
+// It receives the IO of the command execution in addition to the parsed arguments and options,
+// writes its result to stdio.Out instead of returning it, and returns an error if the execution fails.
+type StreamAction func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error
+
 // NewCommand constructs a new Command object with the given name and action,
 // then It Generates the param.Argument and param.Option pointer slice.
 func NewCommand(name string, action Action) Command {
@@ -38,27 +54,77 @@ func NewCommand(name string, action Action) Command {
 	}
 }
 
+// NewStreamCommand constructs a new Command object with the given name and streaming action,
+// then It Generates the param.Argument and param.Option pointer slice.
+func NewStreamCommand(name string, action StreamAction) Command {
+	return Command{
+		Name:         name,
+		arguments:    make([]*param.Argument, 0),
+		options:      make([]*param.Option, 0),
+		streamAction: action,
+	}
+}
+
 // Usage generates a usage string for the command that includes its name,
 // a placeholder for arguments, and a placeholder for options if the command has any.
+// The constraints between options declared by option groups are listed below it.
 // The generated string is intended to be shown to users to demonstrate how to use the command.
 func (c *Command) Usage() string {
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Usage: %s", c.Name))
+	builder.WriteString(Sprintf("Usage: %s", c.Name))
 	if len(c.arguments) > 0 {
-		builder.WriteString(" [arguments]")
+		builder.WriteString(Translate(" [arguments]"))
 	}
-	if len(c.options) > 0 {
-		builder.WriteString(" [options]")
+	if len(c.visibleOptions()) > 0 {
+		builder.WriteString(Translate(" [options]"))
+	}
+	if len(c.groups) > 0 {
+		builder.WriteString("\n" + Translate("Option constraints:"))
+		for _, group := range c.groups {
+			builder.WriteString(fmt.Sprintf("\n  %s", group))
+		}
 	}
 	return builder.String()
 }
 
+// Help generates the help text of the command: its usage followed by
+// the list of its arguments and options with their descriptions.
+// Hidden options are omitted and a deprecated command is described as such.
+func (c *Command) Help() string {
+	var builder strings.Builder
+	builder.WriteString(c.Usage())
+	builder.WriteString("\n")
+	if len(c.arguments) > 0 {
+		rows := make([][2]string, 0, len(c.arguments))
+		for _, argument := range c.arguments {
+			rows = append(rows, [2]string{
+				fmt.Sprintf("%s <%s>", argument.Name, param.ParameterTypeToString(argument.Type)),
+				argument.Description,
+			})
+		}
+		builder.WriteString(Translate("Arguments:") + "\n")
+		builder.WriteString(formatRows(rows))
+	}
+	if options := c.visibleOptions(); len(options) > 0 {
+		rows := make([][2]string, 0, len(options))
+		for _, option := range options {
+			rows = append(rows, [2]string{option.DisplayName(), option.Description})
+		}
+		builder.WriteString(Translate("Options:") + "\n")
+		builder.WriteString(formatRows(rows))
+	}
+	if c.deprecated != nil {
+		builder.WriteString(Sprintf("This command is %s", c.deprecated.Note()) + "\n")
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
 // AddArgument check whether given arg name is duplicate or not,
 // then add it to param.Argument slice.
 func (c *Command) AddArgument(arg *param.Argument) error {
 	for _, argument := range c.arguments {
 		if arg.Name == argument.Name {
-			return fmt.Errorf("duplicate argument name %s", arg.Name)
+			return Errorf("duplicate argument name %s", arg.Name)
 		}
 	}
 	c.arguments = append(c.arguments, arg)
@@ -68,66 +134,228 @@ func (c *Command) AddArgument(arg *param.Argument) error {
 // AddOption check whether given opt name is duplicate or not,
 // then add it to param.Option slice.
 func (c *Command) AddOption(opt *param.Option) error {
-	for _, option := range c.options {
-		if opt.Name == option.Name {
-			return fmt.Errorf("duplicate option name %s", opt.Name)
-		}
+	if c.hasOption(opt.Name) || (opt.Negatable && c.hasOption(opt.NegatedName())) {
+		return Errorf("duplicate option name %s", opt.Name)
+	}
+	if opt.Short != "" && c.hasOption(opt.Short) {
+		return Errorf("duplicate option name %s", opt.Short)
 	}
 	c.options = append(c.options, opt)
 	return nil
 }
 
+// hasOption reports whether the command has an option with the given name,
+// including the `--no-<name>` form of negatable flags.
+func (c *Command) hasOption(name string) bool {
+	option, _ := lookupOption(c.options, name)
+	return option != nil
+}
+
+// lookupOption returns the option with the given name or short form, or the negatable flag option
+// whose `--no-<name>` form is the given name, in which case negated is true.
+// It returns nil if no option matches.
+func lookupOption(options []*param.Option, name string) (option *param.Option, negated bool) {
+	for _, option := range options {
+		if option.Name == name || (option.Short != "" && option.Short == name) {
+			return option, false
+		}
+		if option.Negatable && option.NegatedName() == name {
+			return option, true
+		}
+	}
+	return nil, false
+}
+
 // Execute runs the command with the provided input parameters.
 // It separates the input parameters into arguments and options,
 // validates them, and then calls the commands' Action function.
 // It returns the result-string of the Action function or an error encountered during validation or execution.
+// A StreamAction is executed with the standard streams of the process.
 func (c *Command) Execute(inputParams []string) (string, error) {
-	args, opts, err := c.validate(inputParams)
+	return c.ExecuteWithIO(NewIO(), inputParams)
+}
+
+// ExecuteWithIO runs the command like Execute, but executes a StreamAction with the given IO.
+// It returns the result-string of an Action, or an empty string for a StreamAction
+// whose output has already been written to stdio.Out.
+func (c *Command) ExecuteWithIO(stdio *IO, inputParams []string) (string, error) {
+	return c.execute(inputParams, execution{stdio: stdio})
+}
+
+// Hide hides the command from the help output and completions of the parser.
+// A hidden command can still be executed.
+func (c *Command) Hide() {
+	c.hidden = true
+}
+
+// Deprecate marks the command as deprecated, with the command to use instead and a message, which may be empty.
+// A warning is written to the error stream whenever the command is executed.
+func (c *Command) Deprecate(replacement string, message string) {
+	c.deprecated = &param.Deprecation{Replacement: replacement, Message: message}
+}
+
+// visibleOptions returns the options of the command that are not hidden.
+func (c *Command) visibleOptions() []*param.Option {
+	options := make([]*param.Option, 0, len(c.options))
+	for _, option := range c.options {
+		if !option.Hidden {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// Use appends middlewares that wrap the action of this command.
+// They run inside any parser-wide middlewares, in the order they are added.
+func (c *Command) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// execution holds the state a Parser passes to the command it executes.
+// The config holds option values by option name without the `--` prefix.
+type execution struct {
+	stdio       *IO
+	globalOpts  map[string]param.Value
+	middlewares []Middleware
+	prompt      promptFunc
+	config      map[string]string
+}
+
+// execute validates the input parameters and invokes the action of the command
+// wrapped by the middlewares of the execution followed by the command's own middlewares.
+// The global options of the execution are merged into the options passed to the action.
+func (c *Command) execute(inputParams []string, exec execution) (string, error) {
+	if c.rawAction != nil {
+		return "", c.rawAction(exec.stdio, inputParams)
+	}
+	args, opts, sources, err := c.validate(inputParams, exec)
 	if err != nil {
 		return "", err
 	}
+	for name, value := range exec.globalOpts {
+		opts[name] = value
+	}
+	return c.run(exec, args, opts, sources)
+}
+
+// run warns about the deprecated command and options given in sources, then invokes the action
+// wrapped by the middlewares of the execution followed by the command's own middlewares.
+func (c *Command) run(exec execution, args map[string]param.Value, opts map[string]param.Value, sources map[string]ValueSource) (string, error) {
+	if err := c.warnDeprecated(exec.stdio, sources); err != nil {
+		return "", err
+	}
+
+	middlewares := make([]Middleware, 0, len(exec.middlewares)+len(c.middlewares))
+	middlewares = append(middlewares, exec.middlewares...)
+	middlewares = append(middlewares, c.middlewares...)
+	handler := chainMiddlewares(c.invoke, c, middlewares)
+	return handler(exec.stdio, args, opts)
+}
+
+// warnDeprecated writes a warning to stdio.Err if the command is deprecated,
+// and for each deprecated option given on the command line.
+func (c *Command) warnDeprecated(stdio *IO, sources map[string]ValueSource) error {
+	if c.deprecated != nil {
+		if _, err := fmt.Fprintln(stdio.Err, c.deprecated.Warning("command", c.Name)); err != nil {
+			return err
+		}
+	}
+	return warnDeprecatedOptions(stdio, c.options, sources)
+}
 
+// warnDeprecatedOptions writes a warning to stdio.Err for each deprecated option given on the command line.
+// The keys of sources are the option names without the `--` prefix.
+func warnDeprecatedOptions(stdio *IO, options []*param.Option, sources map[string]ValueSource) error {
+	for _, option := range options {
+		if option.Deprecated != nil && sources[strings.TrimPrefix(option.Name, optionPrefix)] == SourceFlag {
+			if _, err := fmt.Fprintln(stdio.Err, option.Deprecated.Warning("option", option.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// invoke calls the action of the command with the parsed arguments and options.
+// A StreamAction takes precedence over an Action when both are set.
+func (c *Command) invoke(stdio *IO, args map[string]param.Value, opts map[string]param.Value) (string, error) {
+	if c.streamAction != nil {
+		return "", c.streamAction(stdio, args, opts)
+	}
 	return c.action(args, opts)
 }
 
 // validate parses and validates the input parameters for the command.
 // It separates the input parameters into arguments and options,
 // checks them against the command's requirements, and returns
-// a slice of arguments and a map of option if they are valid.
+// a map of arguments, a map of options and the source of each value if they are valid.
+// Options that are not given are taken from their environment variable or the config of the execution.
 // It returns an error if there are too few or too many arguments,
-// or if an invalid option is provided.
-func (c *Command) validate(inputParams []string) (map[string]param.Value, map[string]param.Value, error) {
+// if an invalid option is provided, if a value is rejected by a validator,
+// or if the given options violate an option group.
+// Missing arguments are asked for with the prompt of the execution unless it is nil.
+func (c *Command) validate(inputParams []string, exec execution) (map[string]param.Value, map[string]param.Value, map[string]ValueSource, error) {
+	inputParams = expandShortOptions(c.options, inputParams)
 	args := make(map[string]param.Value)
 	opts := c.initializeOptions()
 	flagOpts := c.flagOptions()
+	given := make(map[string]bool)
+	negatedGiven := make(map[string]bool)
+	sources := make(map[string]ValueSource)
 	optionNow := false
 
 	for i := 0; i < len(inputParams); i++ {
 		p := inputParams[i]
 		if !optionNow && isArgument(p) {
 			if i >= len(c.arguments) {
-				return nil, nil, fmt.Errorf("too many arguments: expected %d", len(c.arguments))
+				return nil, nil, nil, Errorf("too many arguments: expected %d", len(c.arguments))
 			}
 			argName := c.arguments[i].Name
 			argValue, err := c.parseArgument(p, i)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 			args[argName] = *argValue
+			sources[argName] = SourceFlag
 		} else {
 			optionNow = true
 			optName, optValue, err := c.parseOption(p, inputParams, &i, flagOpts)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
+			}
+			option, negated := lookupOption(c.options, p)
+			if option.IsFlag && option.Negatable && given[optName] && negatedGiven[optName] != negated {
+				return nil, nil, nil, Errorf("flag-option --%s and --no-%s cannot be used together", optName, optName)
+			}
+			if option.IsCount {
+				optValue.IntVal += opts[optName].IntVal
+			}
+			if previous, ok := opts[optName]; ok && given[optName] && (option.Type == param.MAP || option.Type == param.LIST) {
+				optValue, err = option.Merge(previous, *optValue)
+				if err != nil {
+					return nil, nil, nil, Errorf("invalid option \"%s\": %w", option.Name, err)
+				}
 			}
 			opts[optName] = *optValue
+			given[optName] = true
+			negatedGiven[optName] = negated
+			sources[optName] = SourceFlag
 		}
 	}
 
-	if err := c.validateArguments(args); err != nil {
-		return nil, nil, err
+	if err := c.validateArguments(args, exec.prompt, sources); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := resolveOptions(c.options, opts, sources, exec.config); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := c.validateValues(args, opts); err != nil {
+		return nil, nil, nil, err
 	}
-	return args, opts, nil
+	if err := c.validateOptionGroups(given, negatedGiven); err != nil {
+		return nil, nil, nil, err
+	}
+	return args, opts, sources, nil
 }
 
 func (c *Command) parseArgument(argParam string, idx int) (*param.Value, error) {
@@ -144,85 +372,129 @@ func (c *Command) parseArgument(argParam string, idx int) (*param.Value, error)
 // It returns the name of the option, its value, and an error if the option is invalid
 // or if there's a problem processing the value.
 func (c *Command) parseOption(optParam string, inputParams []string, idxPtr *int, flagOpts []*param.Option) (string, *param.Value, error) {
-	optType, isFlag, err := c.getOptionTypeAndFlag(optParam)
+	option, err := c.getOption(optParam)
 	if err != nil {
 		return "", nil, err
 	}
 
-	if isFlag {
+	if option.IsFlag {
 		return c.processFlagOption(optParam, inputParams, idxPtr, flagOpts)
 	} else {
-		return c.processRegularOption(optParam, optType, inputParams, idxPtr)
+		return processRegularOption(optParam, option, inputParams, idxPtr)
 	}
 }
 
 // validateArguments checks if the correct number of the arguments has been provided for the command.
+// Missing arguments are asked for in order with prompt unless it is nil or runs out of input,
+// and their source is recorded as SourcePrompt.
 // It returns an error if the number of provided arguments is less than required or greater than allowed.
-func (c *Command) validateArguments(args map[string]param.Value) error {
+func (c *Command) validateArguments(args map[string]param.Value, prompt promptFunc, sources map[string]ValueSource) error {
+	if prompt != nil {
+		for _, argument := range c.arguments[len(args):] {
+			value, err := prompt(argument)
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				break
+			}
+			args[argument.Name] = *value
+			sources[argument.Name] = SourcePrompt
+		}
+	}
 	if len(args) < len(c.arguments) {
-		return fmt.Errorf("not enough arguments: actual %d, expected %d", len(args), len(c.arguments))
+		return Errorf("not enough arguments: actual %d, expected %d", len(args), len(c.arguments))
+	}
+	return nil
+}
+
+// validateValues runs the validators of the arguments and options against their converted values.
+// Options that were not given and have no default value are not validated.
+// It returns the first error naming the parameter and the failing rule.
+func (c *Command) validateValues(args map[string]param.Value, opts map[string]param.Value) error {
+	for _, argument := range c.arguments {
+		if value, ok := args[argument.Name]; ok {
+			if err := argument.Validate(value); err != nil {
+				return err
+			}
+		}
+	}
+	return validateOptionValues(c.options, opts)
+}
+
+// validateOptionValues runs the validators of the options against their values in opts.
+func validateOptionValues(options []*param.Option, opts map[string]param.Value) error {
+	for _, option := range options {
+		if value, ok := opts[strings.TrimPrefix(option.Name, optionPrefix)]; ok {
+			if err := option.Validate(value); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
 // initializeOptions creates a map with default values for all options defined in the command.
-// It initializes flags with a default boolean value of false.
+// It initializes flags with a default boolean value of false, and counting options with zero.
 // The map is used to store the values of options parses from the input parameters.
 func (c *Command) initializeOptions() map[string]param.Value {
 	options := make(map[string]param.Value)
 	for _, option := range c.options {
 		if option.IsFlag {
-			options[strings.TrimPrefix(option.Name, optionPrefix)] = *param.NewBoolParameterPtr(false)
+			options[strings.TrimPrefix(option.Name, optionPrefix)] = *flagDefault(option)
 		}
 	}
 	return options
 }
 
-// getOptionTypeAndFlag  retrieves the type and flag status of the option with the given name.
-// It returns the type of the option, a boolean indicating if it's a flag,
-// if the option does not exist in the command's options list.
-func (c *Command) getOptionTypeAndFlag(optionName string) (param.Type, bool, error) {
-	for _, option := range c.options {
-		if option.Name == optionName {
-			return option.Type, option.IsFlag, nil
-		}
+// getOption retrieves the option with the given name.
+// It returns an error if the option does not exist in the command's options list.
+func (c *Command) getOption(optionName string) (*param.Option, error) {
+	if option, _ := lookupOption(c.options, optionName); option != nil {
+		return option, nil
 	}
-	return -1, false, fmt.Errorf("invalid option %s", optionName)
+	return nil, Errorf("invalid option %s", optionName)
 }
 
 // processFlagOption processes a flag option from the input parameters.
 // A flag option does not take a value; it is simply present or absent.
+// The `--no-<name>` form of a negatable flag sets its value to false,
+// and a counting option has the value 1 for each occurrence.
 // The method updates the flagOpts slice to remove processed options and
 // returns the name of the option and its boolean value.
 func (c *Command) processFlagOption(optionName string, inputParams []string, idxPtr *int, flagOpts []*param.Option) (string, *param.Value, error) {
 	// Make sure the next parameter is not an argument not starting with `--`
 	// Flag Option cannot have value
 	if *idxPtr+1 < len(inputParams) && isArgument(inputParams[*idxPtr+1]) {
-		return "", nil, fmt.Errorf("flag-option %s cannot have value", optionName)
+		return "", nil, Errorf("flag-option %s cannot have value", optionName)
 	}
 
-	c.removeFlagOption(optionName, flagOpts)
-	optionName = strings.TrimPrefix(optionName, optionPrefix)
-	return optionName, param.NewBoolParameterPtr(true), nil
+	option, negated := lookupOption(c.options, optionName)
+	c.removeFlagOption(option.Name, flagOpts)
+	optionName = strings.TrimPrefix(option.Name, optionPrefix)
+	if option.IsCount {
+		return optionName, param.NewIntegerParameterPtr(1), nil
+	}
+	return optionName, param.NewBoolParameterPtr(!negated), nil
 }
 
 // processRegularOption processes a regular (non-flag) option.
 // It checks if the next parameter is a valid value for the option.
 // parses it, and returns the option's name and its parsed value.
-func (c *Command) processRegularOption(optionName string, optType param.Type, inputParams []string, idxPtr *int) (string, *param.Value, error) {
+func processRegularOption(optionName string, option *param.Option, inputParams []string, idxPtr *int) (string, *param.Value, error) {
 	// Make sure the  next parameter is not an option starting with `--`
 	// Whether normal option is last parameter or not
 	// Normal Option always accepts one argument
 	if *idxPtr+1 >= len(inputParams) || !isArgument(inputParams[*idxPtr+1]) {
-		typeStr := param.ParameterTypeToString(optType)
-		return "", nil, fmt.Errorf("\"%s\" option requires a \"%s\" type argument", optionName, typeStr)
+		typeStr := param.ParameterTypeToString(option.Type)
+		return "", nil, Errorf("\"%s\" option requires a \"%s\" type argument", optionName, typeStr)
 	}
 
 	*idxPtr++
 	value := inputParams[*idxPtr]
-	paramValue, err := param.ToParameterValue(value, optType)
+	paramValue, err := option.ParseValue(value)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid option \"%s\": %w", optionName, err)
+		return "", nil, Errorf("invalid option \"%s\": %w", optionName, err)
 	}
 
 	optionName = strings.TrimPrefix(optionName, optionPrefix)
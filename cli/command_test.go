@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"rabbit-todo/cli/param"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -707,3 +711,502 @@ func TestCommand_Execute_Integration(t *testing.T) {
 		})
 	}
 }
+
+func TestCommand_ExecuteWithIO(t *testing.T) {
+	testStreamAction := func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		input, err := io.ReadAll(stdio.In)
+		if err != nil {
+			return err
+		}
+		if opts["fail"].BoolVal {
+			return fmt.Errorf("failed to echo %s", args["prefix"].StringVal)
+		}
+		_, _ = fmt.Fprintf(stdio.Err, "echoing %d bytes\n", len(input))
+		_, err = fmt.Fprintf(stdio.Out, "%s%s", args["prefix"].StringVal, input)
+		return err
+	}
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return args["prefix"].StringVal, nil
+	}
+
+	type inputType struct {
+		command     Command
+		stdin       string
+		inputParams []string
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		want       string
+		wantStdout string
+		wantStderr string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	prefixArg, _ := param.NewArgument("prefix", param.STRING)
+	failOption, _ := param.NewFlagOption("--fail")
+	streamCommand := NewStreamCommand("stream-command", testStreamAction)
+	_ = streamCommand.AddArgument(prefixArg)
+	_ = streamCommand.AddOption(failOption)
+	legacyCommand := NewCommand("legacy-command", testAction)
+	_ = legacyCommand.AddArgument(prefixArg)
+
+	tests := []testCase{
+		{
+			testName: "Ok-StreamAction",
+			input: inputType{
+				command:     streamCommand,
+				stdin:       "piped task",
+				inputParams: []string{"> "},
+			},
+			want:       "",
+			wantStdout: "> piped task",
+			wantStderr: "echoing 10 bytes\n",
+		},
+		{
+			testName: "Ok-LegacyAction",
+			input: inputType{
+				command:     legacyCommand,
+				stdin:       "ignored",
+				inputParams: []string{"returned"},
+			},
+			want:       "returned",
+			wantStdout: "",
+			wantStderr: "",
+		},
+		{
+			testName: "Error-StreamActionFails",
+			input: inputType{
+				command:     streamCommand,
+				stdin:       "",
+				inputParams: []string{"prefix", "--fail"},
+			},
+			wantErr:    true,
+			wantErrStr: "failed to echo prefix",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			stdio := &IO{In: strings.NewReader(tc.input.stdin), Out: &stdout, Err: &stderr}
+			got, err := tc.input.command.ExecuteWithIO(stdio, tc.input.inputParams)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.ExecuteWithIO() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.ExecuteWithIO() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if got != tc.want {
+				t.Errorf("Command.ExecuteWithIO() = %q, want %q", got, tc.want)
+			}
+			if stdout.String() != tc.wantStdout {
+				t.Errorf("Command.ExecuteWithIO() stdout = %q, want %q", stdout.String(), tc.wantStdout)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("Command.ExecuteWithIO() stderr = %q, want %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+}
+
+func TestCommand_Execute_With_Validators(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("%s:%d", args["title"].StringVal, opts["priority"].IntVal), nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	titleArg.AddValidators(param.NotEmpty())
+	priorityOption, _ := param.NewOption("--priority", param.INT)
+	priorityOption.AddValidators(param.Min(1), param.Max(5))
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddOption(priorityOption)
+
+	tests := []testCase{
+		{
+			testName: "Ok-ValidValues",
+			args:     []string{"Buy milk", "--priority", "5"},
+			want:     "Buy milk:5",
+		},
+		{
+			testName: "Ok-OptionNotGiven",
+			args:     []string{"Buy milk"},
+			want:     "Buy milk:0",
+		},
+		{
+			testName:   "Error-EmptyTitle",
+			args:       []string{"", "--priority", "1"},
+			wantErr:    true,
+			wantErrStr: "invalid argument \"title\": not-empty: must not be empty",
+		},
+		{
+			testName:   "Error-PriorityOutOfRange",
+			args:       []string{"Buy milk", "--priority", "0"},
+			wantErr:    true,
+			wantErrStr: "invalid option \"--priority\": min(1): 0 is less than 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Command.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Execute_With_NegatableFlagOptions(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("archive:%t done:%t", opts["archive"].BoolVal, opts["done"].BoolVal), nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	archiveOption, _ := param.NewNegatableFlagOption("--archive")
+	doneOption, _ := param.NewOption("--done", param.BOOL)
+	command := NewCommand("list", testAction)
+	_ = command.AddOption(archiveOption)
+	_ = command.AddOption(doneOption)
+
+	tests := []testCase{
+		{
+			testName: "Ok-Default",
+			args:     []string{},
+			want:     "archive:false done:false",
+		},
+		{
+			testName: "Ok-Positive",
+			args:     []string{"--archive"},
+			want:     "archive:true done:false",
+		},
+		{
+			testName: "Ok-Negated",
+			args:     []string{"--no-archive"},
+			want:     "archive:false done:false",
+		},
+		{
+			testName: "Ok-SameFormTwice",
+			args:     []string{"--no-archive", "--no-archive"},
+			want:     "archive:false done:false",
+		},
+		{
+			testName: "Ok-BoolOptionGivenTwice",
+			args:     []string{"--done", "true", "--done", "false"},
+			want:     "archive:false done:false",
+		},
+		{
+			testName:   "Error-BothForms",
+			args:       []string{"--archive", "--no-archive"},
+			wantErr:    true,
+			wantErrStr: "flag-option --archive and --no-archive cannot be used together",
+		},
+		{
+			testName:   "Error-NegatedFlagHasValue",
+			args:       []string{"--no-archive", "yes"},
+			wantErr:    true,
+			wantErrStr: "flag-option --no-archive cannot have value",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Command.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Error-DuplicateNegatedName", func(t *testing.T) {
+		noArchiveOption, _ := param.NewFlagOption("--no-archive")
+		if err := command.AddOption(noArchiveOption); err == nil || err.Error() != "duplicate option name --no-archive" {
+			t.Errorf("Command.AddOption() error = %v, wantErrStr %q", err, "duplicate option name --no-archive")
+		}
+	})
+}
+
+func TestCommand_Help(t *testing.T) {
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	titleArg.Description = "Title of the task"
+	archiveOption, _ := param.NewNegatableFlagOption("--archive")
+	archiveOption.Description = "Archive the task when done"
+	priorityOption, _ := param.NewOption("--priority", param.INT)
+	command := NewCommand("add", nil)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddOption(archiveOption)
+	_ = command.AddOption(priorityOption)
+
+	want := "Usage: add [arguments] [options]\n" +
+		"Arguments:\n" +
+		"  title <string>  Title of the task\n" +
+		"Options:\n" +
+		"  --[no-]archive    Archive the task when done\n" +
+		"  --priority <int>"
+	if got := command.Help(); got != want {
+		t.Errorf("Command.Help() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_Execute_With_CountAndShortOptions(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("%s verbose:%d quiet:%t priority:%d",
+			args["title"].StringVal, opts["verbose"].IntVal, opts["quiet"].BoolVal, opts["priority"].IntVal), nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	verboseOption, _ := param.NewCountOption("--verbose")
+	_ = verboseOption.SetShort("-v")
+	quietOption, _ := param.NewFlagOption("--quiet")
+	_ = quietOption.SetShort("-q")
+	priorityOption, _ := param.NewOption("--priority", param.INT)
+	_ = priorityOption.SetShort("-p")
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddOption(verboseOption)
+	_ = command.AddOption(quietOption)
+	_ = command.AddOption(priorityOption)
+
+	tests := []testCase{
+		{
+			testName: "Ok-NotGiven",
+			args:     []string{"milk"},
+			want:     "milk verbose:0 quiet:false priority:0",
+		},
+		{
+			testName: "Ok-LongForms",
+			args:     []string{"milk", "--verbose", "--verbose"},
+			want:     "milk verbose:2 quiet:false priority:0",
+		},
+		{
+			testName: "Ok-CombinedShortForms",
+			args:     []string{"milk", "-vqv", "-v"},
+			want:     "milk verbose:3 quiet:true priority:0",
+		},
+		{
+			testName: "Ok-ShortOptionWithValue",
+			args:     []string{"milk", "-p", "2", "-v"},
+			want:     "milk verbose:1 quiet:false priority:2",
+		},
+		{
+			testName: "Ok-DashArgumentIsNotExpanded",
+			args:     []string{"-vx"},
+			want:     "-vx verbose:0 quiet:false priority:0",
+		},
+		{
+			testName:   "Error-CombinedFormWithValueOption",
+			args:       []string{"milk", "-vp", "2"},
+			wantErr:    true,
+			wantErrStr: "too many arguments: expected 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Command.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Execute_With_MapOptions(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		meta := opts["meta"].MapVal
+		keys := make([]string, 0, len(meta))
+		for key := range meta {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value := meta[key]
+			entries = append(entries, fmt.Sprintf("%s=%v", key, value.Value()))
+		}
+		return strings.Join(entries, " "), nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		policy     param.DuplicateKeyPolicy
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	tests := []testCase{
+		{
+			testName: "Ok-Occurrences",
+			args:     []string{"--meta", "jira=ABC-12", "--meta", "owner=kenji"},
+			want:     "jira=ABC-12 owner=kenji",
+		},
+		{
+			testName: "Ok-CommaSeparated",
+			args:     []string{"--meta", "jira=ABC-12,owner=kenji"},
+			want:     "jira=ABC-12 owner=kenji",
+		},
+		{
+			testName: "Ok-NotGiven",
+			args:     []string{},
+			want:     "",
+		},
+		{
+			testName: "Ok-DuplicateKeyLast",
+			args:     []string{"--meta", "owner=kenji", "--meta", "owner=mei"},
+			policy:   param.DuplicateKeyLast,
+			want:     "owner=mei",
+		},
+		{
+			testName:   "Error-DuplicateKey",
+			args:       []string{"--meta", "owner=kenji", "--meta", "owner=mei"},
+			wantErr:    true,
+			wantErrStr: "invalid option \"--meta\": duplicate key owner",
+		},
+		{
+			testName:   "Error-InvalidEntry",
+			args:       []string{"--meta", "owner"},
+			wantErr:    true,
+			wantErrStr: "invalid option \"--meta\": invalid map entry \"owner\": expected key=value",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			metaOption, _ := param.NewMapOption("--meta", param.STRING)
+			metaOption.DuplicateKeys = tc.policy
+			command := NewCommand("add", testAction)
+			_ = command.AddOption(metaOption)
+
+			got, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Command.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Ok-MapArgument", func(t *testing.T) {
+		metaArg, _ := param.NewArgument("meta", param.MAP)
+		command := NewCommand("add", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+			value := args["meta"]
+			return fmt.Sprintf("%v", value.Value()), nil
+		})
+		_ = command.AddArgument(metaArg)
+
+		got, err := command.Execute([]string{"jira=ABC-12,owner=kenji"})
+		if err != nil {
+			t.Fatalf("Command.Execute() error = %v", err)
+		}
+		if want := "map[jira:ABC-12 owner:kenji]"; got != want {
+			t.Errorf("Command.Execute() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestCommand_Execute_With_ListOptions(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		tags := opts["tags"]
+		ids := opts["ids"]
+		return fmt.Sprintf("tags:%v ids:%v", tags.Value(), ids.Value()), nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	tagsOption, _ := param.NewListOption("--tags", param.STRING)
+	idsOption, _ := param.NewListOption("--ids", param.INT)
+	command := NewCommand("tag", testAction)
+	_ = command.AddOption(tagsOption)
+	_ = command.AddOption(idsOption)
+
+	tests := []testCase{
+		{
+			testName: "Ok-Delimited",
+			args:     []string{"--tags", "work,home", "--ids", "1,2"},
+			want:     "tags:[work home] ids:[1 2]",
+		},
+		{
+			testName: "Ok-Occurrences",
+			args:     []string{"--tags", "work", "--tags", `a\,b,home`},
+			want:     "tags:[work a,b home] ids:",
+		},
+		{
+			testName:   "Error-Element",
+			args:       []string{"--ids", "1,x"},
+			wantErr:    true,
+			wantErrStr: "invalid option \"--ids\": element 2: cannot convert x to Integer",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Command.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
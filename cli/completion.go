@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"rabbit-todo/cli/param"
+	"sort"
+	"strings"
+)
+
+const completeCommandName = "__complete"
+
+// NewCompleteCommand constructs the hidden "__complete" command that the shell completion scripts
+// printed by the command of NewCompletionCommand call as `__complete <words>... <partial>`,
+// passing the words of the command line before the cursor followed by the partial word under it, which may be empty.
+// The parameters are not parsed as options, so that the words can contain the options of any command.
+// It writes each candidate on its own line, followed by a tab and its description if it has one.
+func NewCompleteCommand(p *Parser) Command {
+	return Command{
+		Name:        completeCommandName,
+		Description: "Print the completion candidates for a command line",
+		arguments:   make([]*param.Argument, 0),
+		options:     make([]*param.Option, 0),
+		hidden:      true,
+		rawAction: func(stdio *IO, params []string) error {
+			words, partial := params, ""
+			if len(params) > 0 {
+				words, partial = params[:len(params)-1], params[len(params)-1]
+			}
+			for _, candidate := range p.CompleteCandidates(words, partial) {
+				line := candidate.Value
+				if candidate.Description != "" {
+					line += "\t" + candidate.Description
+				}
+				if _, err := fmt.Fprintln(stdio.Out, line); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Complete returns the sorted values of the candidates returned by CompleteCandidates.
+func (p *Parser) Complete(words []string, partial string) []string {
+	candidates := p.CompleteCandidates(words, partial)
+	values := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		values = append(values, candidate.Value)
+	}
+	return values
+}
+
+// CompleteCandidates returns the candidates for the partial word that follows the given words on a command line,
+// sorted by value and with their descriptions.
+// Before the command name it suggests command names, aliases and global options.
+// After the command name it suggests the remaining words of multi-word command names and the values of the next argument,
+// or the options of the command and the global options when the partial word starts with '-'.
+// After an option that takes a value, it suggests the values of the option.
+// Values are suggested by the Complete function of the argument or option, or else by the Choices of an argument.
+// Hidden commands and options are never suggested.
+func (p *Parser) CompleteCandidates(words []string, partial string) []param.Candidate {
+	var candidates []param.Candidate
+	command, argIndex, pending, parsed := p.completionContext(words)
+	idx := p.commandIndex(words)
+	switch {
+	case pending != nil:
+		if pending.Complete != nil {
+			candidates = pending.Complete(partial, parsed)
+		}
+	case strings.HasPrefix(partial, "-"):
+		if command != nil {
+			candidates = optionCandidates(command.options)
+		}
+		candidates = append(candidates, optionCandidates(p.options)...)
+	case idx < 0:
+		for _, command := range p.commands {
+			if names := strings.Fields(command.Name); len(names) > 0 && !command.hidden {
+				candidates = append(candidates, param.Candidate{Value: names[0], Description: command.Description})
+			}
+		}
+		for _, alias := range p.aliases {
+			candidates = append(candidates, param.Candidate{Value: alias.Name, Description: alias.Expansion})
+		}
+	default:
+		rest := words[idx+1:]
+		for _, command := range p.commands {
+			if next, ok := nextCommandWord(command.Name, words[idx], rest); ok && !command.hidden {
+				candidates = append(candidates, param.Candidate{Value: next})
+			}
+		}
+		if command != nil && argIndex < len(command.arguments) {
+			candidates = append(candidates, argumentCandidates(command.arguments[argIndex], partial, parsed)...)
+		}
+	}
+	return filterCandidates(candidates, partial)
+}
+
+// completionContext interprets the words before the word being completed, ignoring values that are invalid.
+// It returns the command they name, or nil if there is none yet, the index of the next argument of the command,
+// which is past the last one once an option of the command is given, the option whose value is being completed,
+// if the last word is an option that takes a value, and the values parsed so far.
+func (p *Parser) completionContext(words []string) (*Command, int, *param.Option, map[string]param.Value) {
+	var command *Command
+	var pending *param.Option
+	argIndex := 0
+	parsed := make(map[string]param.Value)
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if pending != nil {
+			if value, err := pending.ParseValue(word); err == nil {
+				parsed[strings.TrimPrefix(pending.Name, optionPrefix)] = *value
+			}
+			pending = nil
+			continue
+		}
+
+		option, negated := lookupOption(p.options, word)
+		if option == nil && command != nil {
+			option, negated = lookupOption(command.options, word)
+			if option != nil {
+				argIndex = len(command.arguments)
+			}
+		}
+		switch {
+		case option != nil && option.IsCount:
+			name := strings.TrimPrefix(option.Name, optionPrefix)
+			parsed[name] = *param.NewIntegerParameterPtr(parsed[name].IntVal + 1)
+		case option != nil && option.IsFlag:
+			parsed[strings.TrimPrefix(option.Name, optionPrefix)] = *param.NewBoolParameterPtr(!negated)
+		case option != nil:
+			pending = option
+		case command == nil && isArgument(word):
+			found, rest := p.findCommand(word, words[i+1:])
+			if found == nil {
+				return nil, 0, nil, parsed
+			}
+			command = found
+			i += len(words[i+1:]) - len(rest)
+		case command != nil && argIndex < len(command.arguments):
+			argument := command.arguments[argIndex]
+			if value, err := param.ToParameterValue(word, argument.Type); err == nil {
+				parsed[argument.Name] = *value
+			}
+			argIndex++
+		}
+	}
+	return command, argIndex, pending, parsed
+}
+
+// optionCandidates returns the names of the options that are not hidden, with their descriptions.
+func optionCandidates(options []*param.Option) []param.Candidate {
+	candidates := make([]param.Candidate, 0, len(options))
+	for _, option := range options {
+		if !option.Hidden {
+			candidates = append(candidates, param.Candidate{Value: option.Name, Description: option.Description})
+		}
+	}
+	return candidates
+}
+
+// argumentCandidates returns the values suggested by the Complete function of the argument,
+// or else its Choices.
+func argumentCandidates(argument *param.Argument, partial string, parsed map[string]param.Value) []param.Candidate {
+	if argument.Complete != nil {
+		return argument.Complete(partial, parsed)
+	}
+	candidates := make([]param.Candidate, 0, len(argument.Choices))
+	for _, choice := range argument.Choices {
+		candidates = append(candidates, param.Candidate{Value: choice})
+	}
+	return candidates
+}
+
+// nextCommandWord returns the word of a multi-word command name that follows
+// the given command name and words, if the command name starts with them.
+func nextCommandWord(commandName string, name string, words []string) (string, bool) {
+	names := strings.Fields(commandName)
+	if len(names) <= len(words)+1 || names[0] != name {
+		return "", false
+	}
+	for i, word := range words {
+		if names[i+1] != word {
+			return "", false
+		}
+	}
+	return names[len(words)+1], true
+}
+
+// filterCandidates returns the candidates that start with prefix, sorted by value.
+// Of candidates with the same value, only the first is kept.
+func filterCandidates(candidates []param.Candidate, prefix string) []param.Candidate {
+	seen := make(map[string]bool)
+	filtered := make([]param.Candidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate.Value, prefix) && !seen[candidate.Value] {
+			seen[candidate.Value] = true
+			filtered = append(filtered, candidate)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Value < filtered[j].Value
+	})
+	return filtered
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"io"
+	"rabbit-todo/cli/param"
+	"strings"
+)
+
+const (
+	completionCommandName = "completion"
+	programPlaceholder    = "{{program}}"
+	functionPlaceholder   = "{{function}}"
+)
+
+// completionScripts are the completion scripts of the supported shells.
+// Each of them calls `<program> __complete <words>... <partial>` and offers the candidates it prints,
+// one per line with an optional tab-separated description.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{program}}
+{{function}}() {
+    local IFS=$'\n' line
+    local output
+    output=$({{program}} __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" "${COMP_WORDS[COMP_CWORD]}" 2>/dev/null) || return
+    COMPREPLY=()
+    for line in $output; do
+        COMPREPLY+=("${line%%$'\t'*}")
+    done
+}
+complete -o default -F {{function}} {{program}}
+`,
+	"zsh": `#compdef {{program}}
+{{function}}() {
+    local -a candidates
+    local line value
+    for line in "${(@f)$({{program}} __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)}"; do
+        [[ -n $line ]] || continue
+        value=${${line%%$'\t'*}//:/\\:}
+        if [[ $line == *$'\t'* ]]; then
+            candidates+=("$value:${line#*$'\t'}")
+        else
+            candidates+=("$value")
+        fi
+    done
+    _describe 'values' candidates
+}
+compdef {{function}} {{program}}
+`,
+	"fish": `# fish completion for {{program}}
+function {{function}}
+    set -l words (commandline -opc)
+    {{program}} __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c {{program}} -f -a '({{function}})'
+`,
+}
+
+// NewCompletionCommand constructs the "completion" command, which prints the completion script
+// of the shell given as its argument, bash, zsh or fish, for the program with the given name.
+// The script completes command lines by calling the hidden "__complete" command of NewCompleteCommand,
+// which must be added to the parser too. It is typically loaded with `source <(program completion bash)`.
+func NewCompletionCommand(program string) Command {
+	command := NewStreamCommand(completionCommandName, func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		script, err := completionScript(args["shell"].StringVal, program)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(stdio.Out, script)
+		return err
+	})
+	command.Description = "Print the shell completion script"
+	command.noPager = true
+	shellArg, _ := param.NewArgument("shell", param.STRING)
+	shellArg.Description = "Shell to complete in: bash, zsh or fish"
+	shellArg.Choices = []string{"bash", "fish", "zsh"}
+	_ = command.AddArgument(shellArg)
+	return command
+}
+
+// completionScript returns the completion script of the shell for the program.
+// Its shell function is named after the program, with the characters that cannot be used in a name replaced by '_'.
+func completionScript(shell string, program string) (string, error) {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return "", Errorf("unsupported shell %s", shell)
+	}
+	function := "_" + strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, program) + "_complete"
+	return strings.NewReplacer(programPlaceholder, program, functionPlaceholder, function).Replace(script), nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewCompletionCommand(t *testing.T) {
+	type testCase struct {
+		testName     string
+		args         []string
+		wantContains []string
+		wantErr      bool
+		wantErrStr   string
+	}
+	tests := []testCase{
+		{
+			testName:     "Ok-Bash",
+			args:         []string{"bash"},
+			wantContains: []string{"_rabbit_todo_complete() {", "rabbit-todo __complete ", "complete -o default -F _rabbit_todo_complete rabbit-todo"},
+		},
+		{
+			testName:     "Ok-Zsh",
+			args:         []string{"zsh"},
+			wantContains: []string{"#compdef rabbit-todo", "rabbit-todo __complete ", "compdef _rabbit_todo_complete rabbit-todo"},
+		},
+		{
+			testName:     "Ok-Fish",
+			args:         []string{"fish"},
+			wantContains: []string{"rabbit-todo __complete ", "complete -c rabbit-todo -f -a '(_rabbit_todo_complete)'"},
+		},
+		{
+			testName:   "Error-UnsupportedShell",
+			args:       []string{"tcsh"},
+			wantErr:    true,
+			wantErrStr: "invalid argument \"shell\": one-of[bash fish zsh]: tcsh is not one of [bash fish zsh]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var stdout bytes.Buffer
+			command := NewCompletionCommand("rabbit-todo")
+			_, err := command.ExecuteWithIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}}, tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.ExecuteWithIO() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Command.ExecuteWithIO() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			for _, want := range tc.wantContains {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("Command.ExecuteWithIO() stdout = %q, want it to contain %q", stdout.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompletionScript_Bash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	binDir := t.TempDir()
+	program := "# records the words it is called with and prints two candidates\n" +
+		"echo \"$*\" > \"$(dirname \"$0\")/args\"\nprintf 'work\\t5 tasks\\nwell\\n'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "todo"), []byte("#!/bin/sh\n"+program), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script, err := completionScript("bash", "todo")
+	if err != nil {
+		t.Fatalf("completionScript() error = %v", err)
+	}
+
+	cmd := exec.Command(bash, "-c", script+`COMP_WORDS=(todo move w); COMP_CWORD=2; _todo_complete; printf '%s\n' "${COMPREPLY[@]}"`)
+	cmd.Env = append(os.Environ(), "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("bash error = %v", err)
+	}
+	if got, want := string(output), "work\nwell\n"; got != want {
+		t.Errorf("COMPREPLY = %q, want %q", got, want)
+	}
+	args, err := os.ReadFile(filepath.Join(binDir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(args), "__complete move w\n"; got != want {
+		t.Errorf("program called with %q, want %q", got, want)
+	}
+}
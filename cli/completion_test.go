@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_Complete(t *testing.T) {
+	dueOption, _ := param.NewOption("--due", param.STRING)
+	doneOption, _ := param.NewFlagOption("--done")
+	noColorOption, _ := param.NewFlagOption("--no-color")
+	list := NewCommand("list", nil)
+	_ = list.AddOption(dueOption)
+	_ = list.AddOption(doneOption)
+
+	parser := NewParser()
+	_ = parser.AddOption(noColorOption)
+	_ = parser.AddCommand(list)
+	_ = parser.AddCommand(NewCommand("add", nil))
+	for _, command := range NewAliasCommands(parser, "") {
+		_ = parser.AddCommand(command)
+	}
+	_ = parser.AddAlias("later", "list --due later")
+
+	type testCase struct {
+		testName string
+		words    []string
+		partial  string
+		want     []string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-CommandNames",
+			words:    nil,
+			partial:  "",
+			want:     []string{"add", "alias", "later", "list"},
+		},
+		{
+			testName: "Ok-CommandNamesWithPrefix",
+			words:    []string{"--no-color"},
+			partial:  "l",
+			want:     []string{"later", "list"},
+		},
+		{
+			testName: "Ok-GlobalOptionsBeforeCommand",
+			words:    nil,
+			partial:  "--",
+			want:     []string{"--no-color"},
+		},
+		{
+			testName: "Ok-CommandAndGlobalOptions",
+			words:    []string{"list"},
+			partial:  "--d",
+			want:     []string{"--done", "--due"},
+		},
+		{
+			testName: "Ok-SubcommandWords",
+			words:    []string{"alias"},
+			partial:  "",
+			want:     []string{"add", "list", "remove"},
+		},
+		{
+			testName: "Ok-NoCandidates",
+			words:    []string{"add"},
+			partial:  "x",
+			want:     []string{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := parser.Complete(tc.words, tc.partial); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parser.Complete() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_CompleteCandidates(t *testing.T) {
+	projects := func(partial string, parsed map[string]param.Value) []param.Candidate {
+		return []param.Candidate{{Value: "home", Description: "3 tasks"}, {Value: "work", Description: "5 tasks"}}
+	}
+	tasks := func(partial string, parsed map[string]param.Value) []param.Candidate {
+		project := parsed["project"].StringVal
+		return []param.Candidate{{Value: "1", Description: project + ": buy milk"}, {Value: "2", Description: project + ": call bob"}}
+	}
+	projectArg, _ := param.NewArgument("project", param.STRING)
+	projectArg.Complete = projects
+	idArg, _ := param.NewArgument("id", param.INT)
+	idArg.Complete = tasks
+	stateArg, _ := param.NewArgument("state", param.STRING)
+	stateArg.Choices = []string{"open", "done"}
+	tagOption, _ := param.NewOption("--tag", param.STRING)
+	tagOption.Description = "tag of the task"
+	tagOption.Complete = func(partial string, parsed map[string]param.Value) []param.Candidate {
+		return []param.Candidate{{Value: "errand"}, {Value: "email"}}
+	}
+	verboseOption, _ := param.NewCountOption("--verbose")
+	move := NewCommand("move", nil)
+	move.Description = "Move a task"
+	_ = move.AddArgument(projectArg)
+	_ = move.AddArgument(idArg)
+	_ = move.AddArgument(stateArg)
+	_ = move.AddOption(tagOption)
+
+	parser := NewParser()
+	_ = parser.AddOption(verboseOption)
+	_ = parser.AddCommand(move)
+	_ = parser.AddCommand(NewCompleteCommand(parser))
+
+	type testCase struct {
+		testName string
+		words    []string
+		partial  string
+		want     []param.Candidate
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-CommandDescriptions",
+			words:    []string{"--verbose"},
+			partial:  "",
+			want:     []param.Candidate{{Value: "move", Description: "Move a task"}},
+		},
+		{
+			testName: "Ok-FirstArgument",
+			words:    []string{"move"},
+			partial:  "",
+			want:     []param.Candidate{{Value: "home", Description: "3 tasks"}, {Value: "work", Description: "5 tasks"}},
+		},
+		{
+			testName: "Ok-ArgumentWithParsedValues",
+			words:    []string{"move", "work"},
+			partial:  "",
+			want:     []param.Candidate{{Value: "1", Description: "work: buy milk"}, {Value: "2", Description: "work: call bob"}},
+		},
+		{
+			testName: "Ok-Choices",
+			words:    []string{"--verbose", "move", "work", "1"},
+			partial:  "d",
+			want:     []param.Candidate{{Value: "done"}},
+		},
+		{
+			testName: "Ok-OptionValue",
+			words:    []string{"move", "work", "1", "open", "--tag"},
+			partial:  "e",
+			want:     []param.Candidate{{Value: "email"}, {Value: "errand"}},
+		},
+		{
+			testName: "Ok-OptionDescriptions",
+			words:    []string{"move"},
+			partial:  "--t",
+			want:     []param.Candidate{{Value: "--tag", Description: "tag of the task"}},
+		},
+		{
+			testName: "Ok-NoArgumentsAfterOptions",
+			words:    []string{"move", "--tag", "email"},
+			partial:  "",
+			want:     []param.Candidate{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := parser.CompleteCandidates(tc.words, tc.partial); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parser.CompleteCandidates() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Ok-CompleteCommand", func(t *testing.T) {
+		var stdout bytes.Buffer
+		parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}})
+		if _, err := parser.Execute([]string{"__complete", "--verbose", "move", "--tag", "e"}); err != nil {
+			t.Fatalf("Parser.Execute() error = %v", err)
+		}
+		if got, want := stdout.String(), "email\nerrand\n"; got != want {
+			t.Errorf("Parser.Execute() stdout = %q, want %q", got, want)
+		}
+
+		stdout.Reset()
+		if _, err := parser.Execute([]string{"__complete", "move", ""}); err != nil {
+			t.Fatalf("Parser.Execute() error = %v", err)
+		}
+		if got, want := stdout.String(), "home\t3 tasks\nwork\t5 tasks\n"; got != want {
+			t.Errorf("Parser.Execute() stdout = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"rabbit-todo/cli/style"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	helpIndent          = "  "
+	helpColumnGap       = "  "
+	uncategorized       = "Commands"
+	otherCategory       = "Other Commands"
+	pluginsHeading      = "Plugins"
+	minDescriptionWidth = 20
+)
+
+// SetCategoryOrder sets the order in which the categories of commands are listed in the help of the parser.
+// Categories that are not given are listed after them in alphabetical order,
+// followed by the commands without a category.
+func (p *Parser) SetCategoryOrder(categories ...string) {
+	p.categories = categories
+}
+
+// Help generates an overview of the commands of the parser grouped by category,
+// followed by the plugin commands that can be found if plugins are enabled.
+// Commands are sorted by name within a category, and their descriptions are aligned in a column
+// and wrapped to the width of the terminal of the parser's output.
+// Hidden commands are omitted and deprecated commands are marked as such.
+// The category headings are bold if the output of the parser is styled.
+func (p *Parser) Help() string {
+	groups := make(map[string][][2]string)
+	categories := 0
+	for _, command := range p.commands {
+		if command.hidden {
+			continue
+		}
+		description := command.Description
+		if command.deprecated != nil {
+			description = strings.TrimSpace(description + " " + Translate("(deprecated)"))
+		}
+		if _, ok := groups[command.Category]; !ok {
+			categories++
+		}
+		groups[command.Category] = append(groups[command.Category], [2]string{command.Name, description})
+	}
+	// Plugins are kept apart from the groups, so that they cannot be confused with a category of the same name.
+	var pluginRows [][2]string
+	for _, plugin := range p.Plugins() {
+		pluginRows = append(pluginRows, [2]string{plugin, ""})
+	}
+
+	nameWidth := nameColumnWidth(pluginRows)
+	for _, rows := range groups {
+		if w := nameColumnWidth(rows); w > nameWidth {
+			nameWidth = w
+		}
+	}
+
+	var builder strings.Builder
+	lineWidth := terminalWidth(p.IO().Out)
+	styler := p.IO().Style()
+	for _, category := range p.orderCategories(groups) {
+		rows := groups[category]
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i][0] < rows[j][0]
+		})
+		heading := category
+		if category == "" {
+			heading = uncategorized
+			if categories > 1 {
+				heading = otherCategory
+			}
+		}
+		builder.WriteString(styler.Render(style.Bold, Translate(heading)+":") + "\n")
+		builder.WriteString(formatColumns(rows, nameWidth, lineWidth))
+	}
+	if len(pluginRows) > 0 {
+		builder.WriteString(styler.Render(style.Bold, Translate(pluginsHeading)+":") + "\n")
+		builder.WriteString(formatColumns(pluginRows, nameWidth, lineWidth))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// orderCategories returns the categories of the groups in the order set by SetCategoryOrder,
+// then the other categories in alphabetical order, then the commands without a category.
+func (p *Parser) orderCategories(groups map[string][][2]string) []string {
+	ordered := make([]string, 0, len(groups))
+	seen := make(map[string]bool)
+	for _, category := range p.categories {
+		if _, ok := groups[category]; ok && !seen[category] {
+			ordered = append(ordered, category)
+			seen[category] = true
+		}
+	}
+
+	var rest []string
+	for category := range groups {
+		if !seen[category] && category != "" {
+			rest = append(rest, category)
+		}
+	}
+	sort.Strings(rest)
+	ordered = append(ordered, rest...)
+
+	if _, ok := groups[""]; ok {
+		ordered = append(ordered, "")
+	}
+	return ordered
+}
+
+// formatRows formats rows of a name and a description as indented lines,
+// with the descriptions aligned in a column after the longest name.
+func formatRows(rows [][2]string) string {
+	return formatColumns(rows, nameColumnWidth(rows), 0)
+}
+
+// nameColumnWidth returns the length of the longest name of the rows.
+func nameColumnWidth(rows [][2]string) int {
+	width := 0
+	for _, row := range rows {
+		if w := utf8.RuneCountInString(row[0]); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// formatColumns formats rows of a name and a description as indented lines,
+// with the names padded to nameWidth. If lineWidth is positive, descriptions are wrapped
+// so that lines do not exceed it, with continuation lines aligned to the description column.
+func formatColumns(rows [][2]string, nameWidth int, lineWidth int) string {
+	descriptionColumn := utf8.RuneCountInString(helpIndent) + nameWidth + utf8.RuneCountInString(helpColumnGap)
+	descriptionWidth := lineWidth - descriptionColumn
+	if lineWidth <= 0 || descriptionWidth < minDescriptionWidth {
+		descriptionWidth = 0
+	}
+
+	var builder strings.Builder
+	for _, row := range rows {
+		builder.WriteString(helpIndent)
+		builder.WriteString(row[0])
+		if row[1] != "" {
+			builder.WriteString(strings.Repeat(" ", nameWidth-utf8.RuneCountInString(row[0])))
+			builder.WriteString(helpColumnGap)
+			lines := wrapText(row[1], descriptionWidth)
+			builder.WriteString(strings.Join(lines, "\n"+strings.Repeat(" ", descriptionColumn)))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// wrapText splits the text into lines of at most width characters, breaking at spaces.
+// Words longer than width are kept whole. A width of zero or less disables wrapping.
+func wrapText(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_Help(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "", nil
+	}
+	newCommand := func(name string, category string, description string) Command {
+		command := NewCommand(name, testAction)
+		command.Category = category
+		command.Description = description
+		return command
+	}
+
+	type testCase struct {
+		testName      string
+		commands      []Command
+		categoryOrder []string
+		columns       string
+		want          string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Uncategorized",
+			commands: []Command{
+				newCommand("list", "", "List tasks"),
+				newCommand("add", "", "Add a task"),
+			},
+			want: "Commands:\n  add   Add a task\n  list  List tasks",
+		},
+		{
+			testName: "Ok-CategoriesAlphabetical",
+			commands: []Command{
+				newCommand("list", "Views", "List tasks"),
+				newCommand("version", "", "Print the version"),
+				newCommand("done", "Tasks", "Complete a task"),
+				newCommand("add", "Tasks", "Add a task"),
+			},
+			want: "Tasks:\n" +
+				"  add      Add a task\n" +
+				"  done     Complete a task\n" +
+				"Views:\n" +
+				"  list     List tasks\n" +
+				"Other Commands:\n" +
+				"  version  Print the version",
+		},
+		{
+			testName: "Ok-CategoryOrder",
+			commands: []Command{
+				newCommand("export", "Import/Export", "Export tasks"),
+				newCommand("gc", "Admin", "Remove archived tasks"),
+				newCommand("add", "Tasks", "Add a task"),
+			},
+			categoryOrder: []string{"Tasks", "Admin"},
+			want: "Tasks:\n" +
+				"  add     Add a task\n" +
+				"Admin:\n" +
+				"  gc      Remove archived tasks\n" +
+				"Import/Export:\n" +
+				"  export  Export tasks",
+		},
+		{
+			testName: "Ok-Wrapped",
+			commands: []Command{
+				newCommand("add", "", "Add a task to the inbox or to the given project"),
+			},
+			columns: "30",
+			want: "Commands:\n" +
+				"  add  Add a task to the inbox\n" +
+				"       or to the given project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Setenv("COLUMNS", tt.columns)
+			parser := NewParser()
+			parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}})
+			parser.SetCategoryOrder(tt.categoryOrder...)
+			for _, command := range tt.commands {
+				if err := parser.AddCommand(command); err != nil {
+					t.Fatalf("Parser.AddCommand() error = %v", err)
+				}
+			}
+			if got := parser.Help(); got != tt.want {
+				t.Errorf("Parser.Help() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	type testCase struct {
+		testName string
+		text     string
+		width    int
+		want     []string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-NoWrap",
+			text:     "Add a task",
+			width:    0,
+			want:     []string{"Add a task"},
+		},
+		{
+			testName: "Ok-Wrapped",
+			text:     "Add a task to the inbox",
+			width:    10,
+			want:     []string{"Add a task", "to the", "inbox"},
+		},
+		{
+			testName: "Ok-LongWord",
+			text:     "Synchronize everything",
+			width:    5,
+			want:     []string{"Synchronize", "everything"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if got := wrapText(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"rabbit-todo/cli/style"
+	"strconv"
+)
+
+// defaultTerminalWidth and defaultTerminalHeight are the size used when the size of the terminal cannot be determined.
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// IO holds the streams a command reads from and writes to.
+// Actions that stream their output receive it instead of returning a single string,
+// so they can read piped input from In and report progress on Err while writing results to Out.
+// Interactive reports whether In is a terminal that a user can answer prompts on,
+// and Color selects when text written to Out is styled.
+type IO struct {
+	In          io.Reader
+	Out         io.Writer
+	Err         io.Writer
+	Interactive bool
+	Color       style.Mode
+
+	// nested is set on the IO passed to a command by the parser, so that the command lines
+	// that the command executes with it in turn, as batch does, are not paged on their own.
+	nested bool
+}
+
+// NewIO constructs a new IO object bound to the standard input, output and error streams of the process.
+func NewIO() *IO {
+	return &IO{
+		In:          os.Stdin,
+		Out:         os.Stdout,
+		Err:         os.Stderr,
+		Interactive: isTerminal(os.Stdin),
+	}
+}
+
+// Style returns a Styler for text written to Out, which renders plain text
+// unless Out is a terminal or the color mode or the environment asks for styles.
+func (stdio *IO) Style() *style.Styler {
+	return style.New(stdio.Out, stdio.Color)
+}
+
+// isTerminal reports whether the file is a character device such as a terminal,
+// as opposed to a pipe or a regular file.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns the number of columns available for output written to w.
+// The COLUMNS environment variable takes precedence over the size of the terminal,
+// and defaultTerminalWidth is used when w is not a terminal.
+func terminalWidth(w io.Writer) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if file, ok := w.(*os.File); ok && isTerminal(file) {
+		if columns, _, err := terminalSize(file); err == nil && columns > 0 {
+			return columns
+		}
+	}
+	return defaultTerminalWidth
+}
+
+// terminalHeight returns the number of lines that fit on the terminal that w writes to.
+// The LINES environment variable takes precedence over the size of the terminal,
+// and defaultTerminalHeight is used when w is not a terminal.
+func terminalHeight(w io.Writer) int {
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+	if file, ok := w.(*os.File); ok && isTerminal(file) {
+		if _, rows, err := terminalSize(file); err == nil && rows > 0 {
+			return rows
+		}
+	}
+	return defaultTerminalHeight
+}
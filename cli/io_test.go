@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNewIO(t *testing.T) {
+	t.Run("Ok-StandardStreams", func(t *testing.T) {
+		got := NewIO()
+		if got.In != os.Stdin {
+			t.Errorf("IO.In = %v, want %v", got.In, os.Stdin)
+		}
+		if got.Out != os.Stdout {
+			t.Errorf("IO.Out = %v, want %v", got.Out, os.Stdout)
+		}
+		if got.Err != os.Stderr {
+			t.Errorf("IO.Err = %v, want %v", got.Err, os.Stderr)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Control keys handled by the line editor in raw mode.
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// lineEditor reads lines from a terminal with basic editing, history and completion.
+// In raw mode it handles the keys itself: cursor movement with the arrow keys, Ctrl-A and Ctrl-E,
+// deletion with Backspace, Delete, Ctrl-U and Ctrl-K, history with the up and down arrow keys,
+// and completion with Tab. Otherwise it reads whole lines as the terminal delivers them.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	raw      bool
+	history  []string
+	complete func(words []string, partial string) []string
+}
+
+// readLine shows the prompt and reads a line without its line terminator.
+// It returns io.EOF when the input ends before anything was typed, or when Ctrl-D is pressed on an empty line.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if _, err := fmt.Fprint(e.out, prompt); err != nil {
+		return "", err
+	}
+	if !e.raw {
+		line, err := e.in.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return "", io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	var buf []rune
+	cursor := 0
+	historyIdx := len(e.history)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err == io.EOF && len(buf) > 0 {
+			_, _ = fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			_, err := fmt.Fprint(e.out, "\r\n")
+			return string(buf), err
+		case keyCtrlD:
+			if len(buf) == 0 {
+				_, _ = fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if cursor < len(buf) {
+				buf = append(buf[:cursor], buf[cursor+1:]...)
+			}
+		case keyCtrlC:
+			_, _ = fmt.Fprint(e.out, "^C\r\n")
+			buf, cursor = nil, 0
+		case keyBackspace, keyDelete:
+			if cursor > 0 {
+				buf = append(buf[:cursor-1], buf[cursor:]...)
+				cursor--
+			}
+		case keyCtrlA:
+			cursor = 0
+		case keyCtrlE:
+			cursor = len(buf)
+		case keyCtrlU:
+			buf = append([]rune{}, buf[cursor:]...)
+			cursor = 0
+		case keyCtrlK:
+			buf = buf[:cursor]
+		case keyTab:
+			buf, cursor = e.completeLine(prompt, buf, cursor)
+		case keyEscape:
+			seq := e.readEscapeSequence()
+			switch seq {
+			case "[A":
+				if historyIdx > 0 {
+					historyIdx--
+					buf = []rune(e.history[historyIdx])
+					cursor = len(buf)
+				}
+			case "[B":
+				if historyIdx < len(e.history) {
+					historyIdx++
+					buf = nil
+					if historyIdx < len(e.history) {
+						buf = []rune(e.history[historyIdx])
+					}
+					cursor = len(buf)
+				}
+			case "[C":
+				if cursor < len(buf) {
+					cursor++
+				}
+			case "[D":
+				if cursor > 0 {
+					cursor--
+				}
+			case "[H":
+				cursor = 0
+			case "[F":
+				cursor = len(buf)
+			case "[3~":
+				if cursor < len(buf) {
+					buf = append(buf[:cursor], buf[cursor+1:]...)
+				}
+			}
+		default:
+			if unicode.IsPrint(r) {
+				buf = append(buf[:cursor], append([]rune{r}, buf[cursor:]...)...)
+				cursor++
+			}
+		}
+		e.redraw(prompt, buf, cursor)
+	}
+}
+
+// readEscapeSequence reads the rest of an ANSI escape sequence after the escape key,
+// up to and including its final letter or '~'.
+func (e *lineEditor) readEscapeSequence() string {
+	var builder strings.Builder
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return builder.String()
+		}
+		builder.WriteRune(r)
+		if builder.Len() > 1 && (unicode.IsLetter(r) || r == '~') {
+			return builder.String()
+		}
+	}
+}
+
+// redraw rewrites the current line and moves the cursor to its position.
+func (e *lineEditor) redraw(prompt string, buf []rune, cursor int) {
+	_, _ = fmt.Fprintf(e.out, "\r\x1b[K%s%s", prompt, string(buf))
+	if back := len(buf) - cursor; back > 0 {
+		_, _ = fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// completeLine completes the word before the cursor.
+// A single candidate replaces the word and is followed by a space, several candidates extend the word
+// to their common prefix, and if that does not extend it, the candidates are listed below the line.
+func (e *lineEditor) completeLine(prompt string, buf []rune, cursor int) ([]rune, int) {
+	if e.complete == nil {
+		return buf, cursor
+	}
+	before := string(buf[:cursor])
+	words := strings.Fields(before)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(before, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	candidates := e.complete(words, partial)
+	if len(candidates) == 0 {
+		return buf, cursor
+	}
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		completion += " "
+	} else if completion == partial {
+		_, _ = fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+		return buf, cursor
+	}
+
+	insert := []rune(strings.TrimPrefix(completion, partial))
+	buf = append(buf[:cursor], append(insert, buf[cursor:]...)...)
+	return buf, cursor + len(insert)
+}
+
+// commonPrefix returns the longest common prefix of the strings.
+// The strings are compared rune by rune, so that the prefix never ends in the middle of a multibyte character.
+func commonPrefix(strs []string) string {
+	prefix := []rune(strs[0])
+	for _, s := range strs[1:] {
+		n := 0
+		for _, r := range s {
+			if n >= len(prefix) || prefix[n] != r {
+				break
+			}
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineEditor_readLine(t *testing.T) {
+	complete := func(words []string, partial string) []string {
+		var candidates []string
+		for _, candidate := range []string{"--done", "--due", "add", "archive", "list"} {
+			if strings.HasPrefix(candidate, partial) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		return candidates
+	}
+
+	type testCase struct {
+		testName string
+		input    string
+		want     string
+		wantErr  error
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-TypeLine",
+			input:    "list\r",
+			want:     "list",
+		},
+		{
+			testName: "Ok-Backspace",
+			input:    "lisd\x7ft\r",
+			want:     "list",
+		},
+		{
+			testName: "Ok-MoveCursorAndInsert",
+			input:    "ist\x01l\x05 --done\x1b[D\x1b[D\x1b[C\r",
+			want:     "list --done",
+		},
+		{
+			testName: "Ok-KillLine",
+			input:    "remove 1\x15add 2\x01\x0b list\r",
+			want:     " list",
+		},
+		{
+			testName: "Ok-HistoryUpAndDown",
+			input:    "\x1b[A\x1b[A\x1b[B\r",
+			want:     "list --done",
+		},
+		{
+			testName: "Ok-CompleteSingleCandidate",
+			input:    "l\t--do\t\r",
+			want:     "list --done ",
+		},
+		{
+			testName: "Ok-CompleteCommonPrefix",
+			input:    "a\tr\t\r",
+			want:     "archive ",
+		},
+		{
+			testName: "Ok-CtrlCClearsLine",
+			input:    "remove 1\x03list\r",
+			want:     "list",
+		},
+		{
+			testName: "Ok-EndOfInputAfterText",
+			input:    "list",
+			want:     "list",
+		},
+		{
+			testName: "Error-CtrlDOnEmptyLine",
+			input:    "\x04",
+			wantErr:  io.EOF,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var out bytes.Buffer
+			editor := &lineEditor{
+				in:       bufio.NewReader(strings.NewReader(tc.input)),
+				out:      &out,
+				raw:      true,
+				history:  []string{"add milk", "list --done"},
+				complete: complete,
+			}
+			got, err := editor.readLine("> ")
+			if err != tc.wantErr {
+				t.Fatalf("lineEditor.readLine() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("lineEditor.readLine() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	type testCase struct {
+		testName string
+		strs     []string
+		want     string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Ascii",
+			strs:     []string{"work", "world"},
+			want:     "wor",
+		},
+		{
+			testName: "Ok-Multibyte",
+			strs:     []string{"あい", "あう"},
+			want:     "あ",
+		},
+		{
+			testName: "Ok-MultibyteSharingLeadingByte",
+			strs:     []string{"あ", "い"},
+			want:     "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := commonPrefix(tc.strs); got != tc.want {
+				t.Errorf("commonPrefix() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"rabbit-todo/cli/param"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultLocale is the locale of the messages written in the source code.
+	DefaultLocale  = "en"
+	langOptionName = "--lang"
+)
+
+// Catalog maps English message formats, such as "unknown command %s", to their translation in one locale.
+// A translation must use the same verbs in the same order as the format it translates.
+type Catalog map[string]string
+
+var (
+	messagesMu sync.RWMutex
+	catalogs   = map[string]Catalog{"ja": japaneseCatalog}
+	locale     string
+)
+
+func init() {
+	param.SetTranslator(Translate)
+}
+
+// RegisterCatalog adds the translations of the catalog to the messages of the locale,
+// so that applications can translate the output of their own actions with Sprintf and Errorf.
+// Translations registered later replace earlier ones for the same format.
+func RegisterCatalog(loc string, catalog Catalog) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	loc = normalizeLocale(loc)
+	if catalogs[loc] == nil {
+		catalogs[loc] = make(Catalog)
+	}
+	for format, translation := range catalog {
+		catalogs[loc][format] = translation
+	}
+}
+
+// SetLocale selects the locale of the messages, such as "ja" or "ja_JP.UTF-8".
+// An empty locale restores the selection from the environment.
+func SetLocale(loc string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	locale = loc
+}
+
+// overrideLocale selects the locale of the messages like SetLocale
+// and returns a function that restores the previous selection.
+func overrideLocale(loc string) func() {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	previous := locale
+	locale = loc
+	return func() {
+		SetLocale(previous)
+	}
+}
+
+// Locale returns the language of the messages, such as "ja".
+// Unless it was selected with SetLocale, it is taken from the LC_ALL, LC_MESSAGES and LANG environment variables
+// in that order, and is DefaultLocale if none of them is set.
+func Locale() string {
+	messagesMu.RLock()
+	loc := locale
+	messagesMu.RUnlock()
+	if loc == "" {
+		loc = localeFromEnv()
+	}
+	return normalizeLocale(loc)
+}
+
+// Translate returns the translation of the message format in the selected locale,
+// or the format itself if the catalog of the locale has no translation for it.
+func Translate(format string) string {
+	loc := Locale()
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	if translation, ok := catalogs[loc][format]; ok {
+		return translation
+	}
+	return format
+}
+
+// Sprintf is fmt.Sprintf with the format translated into the selected locale.
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(Translate(format), args...)
+}
+
+// Errorf is fmt.Errorf with the format translated into the selected locale.
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(Translate(format), args...)
+}
+
+// localeFromEnv returns the first non-empty locale environment variable, in the order of precedence of POSIX.
+func localeFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeLocale reduces a locale such as "ja_JP.UTF-8" to its language "ja".
+// The "C" and "POSIX" locales and the empty locale are DefaultLocale.
+func normalizeLocale(loc string) string {
+	if i := strings.IndexAny(loc, "_.@-"); i >= 0 {
+		loc = loc[:i]
+	}
+	loc = strings.ToLower(loc)
+	if loc == "" || loc == "c" || loc == "posix" {
+		return DefaultLocale
+	}
+	return loc
+}
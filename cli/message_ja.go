@@ -0,0 +1,115 @@
+package cli
+
+// japaneseCatalog translates the messages of the parser and its parameters into Japanese.
+var japaneseCatalog = Catalog{
+	// Help
+	"Usage: %s":               "使い方: %s",
+	" [arguments]":            " [引数]",
+	" [options]":              " [オプション]",
+	"Option constraints:":     "オプションの制約:",
+	"Arguments:":              "引数:",
+	"Options:":                "オプション:",
+	"This command is %s":      "このコマンドは%sです",
+	"Commands":                "コマンド",
+	"Other Commands":          "その他のコマンド",
+	"Plugins":                 "プラグイン",
+	"(deprecated)":            "(非推奨)",
+	"%s (mutually exclusive)": "%s (同時に指定できません)",
+	"%s (required together)":  "%s (同時に指定が必要です)",
+	"%s requires %s":          "%s には %s が必要です",
+
+	// Deprecation
+	"command":              "コマンド",
+	"option":               "オプション",
+	"argument":             "引数",
+	"deprecated":           "非推奨",
+	", use %s instead":     " (代わりに %s を使用してください)",
+	"warning: %s %s is %s": "警告: %s %s は%sです",
+
+	// Parser and commands
+	"no command provided":                                  "コマンドが指定されていません",
+	"unknown command %s":                                   "不明なコマンド %s",
+	"duplicate command name %s":                            "コマンド名 %s が重複しています",
+	"alias %s shadows command %s":                          "エイリアス %s がコマンド %s を隠しています",
+	"duplicate argument name %s":                           "引数名 %s が重複しています",
+	"duplicate option name %s":                             "オプション名 %s が重複しています",
+	"option %s of command %s conflicts with global option": "オプション %s (コマンド %s) がグローバルオプションと競合しています",
+	"invalid option %s":                                    "無効なオプション %s",
+	"invalid option \"%s\": %w":                            "無効なオプション \"%s\": %w",
+	"too many arguments: expected %d":                      "引数が多すぎます: 期待値 %d",
+	"not enough arguments: actual %d, expected %d":         "引数が足りません: 実際 %d、期待値 %d",
+	"flag-option %s cannot have value":                     "フラグオプション %s に値は指定できません",
+	"flag-option --%s and --no-%s cannot be used together": "フラグオプション --%s と --no-%s は同時に使用できません",
+	"invalid value of environment variable %s: %w":         "環境変数 %s の値が無効です: %w",
+	"invalid config value of option %s: %w":                "オプション %s の設定値が無効です: %w",
+	"\"%s\" option requires a \"%s\" type argument":        "オプション \"%s\" には \"%s\" 型の値が必要です",
+
+	// Aliases
+	"invalid alias %s: %w":               "無効なエイリアス %s: %w",
+	"alias %s requires argument %s":      "エイリアス %s には引数 %s が必要です",
+	"invalid alias name %q":              "無効なエイリアス名 %q",
+	"alias %s must not be empty":         "エイリアス %s を空にすることはできません",
+	"duplicate alias name %s":            "エイリアス名 %s が重複しています",
+	"unknown alias %s":                   "不明なエイリアス %s",
+	"recursive alias %s -> %s":           "エイリアスが循環しています: %s -> %s",
+	"%s:%d: invalid alias definition %q": "%s:%d: 無効なエイリアス定義 %q",
+	"added alias %s":                     "エイリアス %s を追加しました",
+	"removed alias %s":                   "エイリアス %s を削除しました",
+
+	// Response files and tokens
+	"cannot resolve response file %s: %w": "レスポンスファイル %s のパスを解決できません: %w",
+	"response file cycle: %s":             "レスポンスファイルが循環しています: %s",
+	"cannot read response file %s: %w":    "レスポンスファイル %s を読み込めません: %w",
+	"invalid response file %s: %w":        "無効なレスポンスファイル %s: %w",
+	"trailing backslash":                  "末尾にバックスラッシュがあります",
+	"unterminated single quote":           "シングルクォートが閉じられていません",
+	"unterminated double quote":           "ダブルクォートが閉じられていません",
+
+	// Plugins, batches and the shell
+	"plugin %s exited with status %d": "プラグイン %s がステータス %d で終了しました",
+	"cannot run plugin %s: %w":        "プラグイン %s を実行できません: %w",
+	"cannot open batch file %s: %w":   "バッチファイル %s を開けません: %w",
+	"%w (rollback failed: %v)":        "%w (ロールバックに失敗しました: %v)",
+	"%d of %d commands failed":        "%d 件のコマンドが失敗しました (全 %d 件)",
+	"batch cannot be nested":          "batch は入れ子にできません",
+	"line %d: %w":                     "%d 行目: %w",
+	"line %d: %v":                     "%d 行目: %v",
+	"unsupported shell %s":            "サポートされていないシェル %s",
+	"already in shell":                "すでにシェルの中です",
+
+	// Output
+	"unknown column %s":        "不明な列 %s",
+	"unknown output format %s": "不明な出力形式 %s",
+
+	// Option groups
+	"option group requires at least two options":   "オプショングループには2つ以上のオプションが必要です",
+	"unknown option %s in option group":            "オプショングループに不明なオプション %s があります",
+	"options %s are mutually exclusive":            "オプション %s は同時に指定できません",
+	"options %s must be used together: missing %s": "オプション %s は同時に指定する必要があります: %s がありません",
+	"option %s requires %s":                        "オプション %s には %s が必要です",
+
+	// Parameters
+	"name must not be empty":                               "名前を空にすることはできません",
+	"name must start with '--'":                            "名前は '--' で始まる必要があります",
+	"name must not start with '--'":                        "名前を '--' で始めることはできません",
+	"short name must be '-' followed by a letter or digit": "短い名前は '-' と英数字1文字である必要があります",
+	"cannot convert %s to Integer":                         "%s を Integer に変換できません",
+	"cannot convert %s to Boolean":                         "%s を Boolean に変換できません",
+	"unknown parameter type %v":                            "不明なパラメータ型 %v",
+	"invalid %s \"%s\": %s: %w":                            "無効な%s \"%s\": %s: %w",
+	"expected int value, got %s":                           "int 型の値が必要ですが %s でした",
+	"expected string value, got %s":                        "string 型の値が必要ですが %s でした",
+	"%d is less than %d":                                   "%d は %d より小さいです",
+	"%d is greater than %d":                                "%d は %d より大きいです",
+	"length %d is less than %d":                            "長さ %d は %d より短いです",
+	"length %d is greater than %d":                         "長さ %d は %d より長いです",
+	"must not be empty":                                    "空にすることはできません",
+	"%s does not match %s":                                 "%s は %s に一致しません",
+	"invalid map entry \"%s\": expected key=value":         "無効なマップ要素 \"%s\": key=value の形式が必要です",
+	"key %s: %w":                                           "キー %s: %w",
+	"element %d: %w":                                       "要素 %d: %w",
+	"invalid list \"%s\": trailing backslash":              "無効なリスト \"%s\": 末尾にバックスラッシュがあります",
+	"duplicate key %s":                                     "キー %s が重複しています",
+	"%s is not one of %v":                                  "%s は %v のいずれでもありません",
+	" (default: %v)":                                       " (デフォルト: %v)",
+}
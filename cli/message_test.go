@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"rabbit-todo/cli/param"
+	"strings"
+	"testing"
+)
+
+// TestMain pins the locale and turns off forced colors, so that the plain English messages
+// expected by the tests do not depend on the environment they run in.
+func TestMain(m *testing.M) {
+	SetLocale(DefaultLocale)
+	_ = os.Unsetenv("CLICOLOR_FORCE")
+	os.Exit(m.Run())
+}
+
+func TestLocale(t *testing.T) {
+	type testCase struct {
+		testName   string
+		locale     string
+		lcAll      string
+		lcMessages string
+		lang       string
+		want       string
+	}
+	tests := []testCase{
+		{testName: "Ok-Default", want: "en"},
+		{testName: "Ok-CLocale", lang: "C", want: "en"},
+		{testName: "Ok-Lang", lang: "ja_JP.UTF-8", want: "ja"},
+		{testName: "Ok-LcMessagesOverLang", lcMessages: "ja_JP.UTF-8", lang: "en_US.UTF-8", want: "ja"},
+		{testName: "Ok-LcAllOverLcMessages", lcAll: "en_US", lcMessages: "ja_JP", want: "en"},
+		{testName: "Ok-SetLocaleOverEnv", locale: "ja", lang: "en_US.UTF-8", want: "ja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+			SetLocale(tt.locale)
+			defer SetLocale(DefaultLocale)
+			if got := Locale(); got != tt.want {
+				t.Errorf("Locale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Execute_Localized(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return Sprintf("added task %s", args["title"].StringVal), nil
+	}
+	RegisterCatalog("ja", Catalog{"added task %s": "タスク %s を追加しました"})
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	priorityOption, _ := param.NewOption("--priority", param.INT)
+	add := NewCommand("add", testAction)
+	_ = add.AddArgument(titleArg)
+	_ = add.AddOption(priorityOption)
+
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}})
+	_ = parser.AddCommand(add)
+	if err := parser.EnableLocaleOption(); err != nil {
+		t.Fatalf("Parser.EnableLocaleOption() error = %v", err)
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-English",
+			args:     []string{"--lang", "en", "add", "milk"},
+			want:     "added task milk",
+		},
+		{
+			testName: "Ok-Japanese",
+			args:     []string{"--lang", "ja", "add", "milk"},
+			want:     "タスク milk を追加しました",
+		},
+		{
+			testName:   "Error-JapaneseCommand",
+			args:       []string{"--lang", "ja", "remove"},
+			wantErrStr: "不明なコマンド remove",
+		},
+		{
+			testName:   "Error-JapaneseParameter",
+			args:       []string{"--lang", "ja", "add", "milk", "--priority", "high"},
+			wantErrStr: "無効なオプション \"--priority\": high を Integer に変換できません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			defer SetLocale(DefaultLocale)
+			got, err := parser.Execute(tt.args)
+			if tt.wantErrStr != "" {
+				if err == nil || err.Error() != tt.wantErrStr {
+					t.Errorf("Parser.Execute() error = %v, wantErrStr %q", err, tt.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parser.Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_LocaleOption_Scoped(t *testing.T) {
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}})
+	if err := parser.EnableLocaleOption(); err != nil {
+		t.Fatalf("Parser.EnableLocaleOption() error = %v", err)
+	}
+	other := NewParser()
+
+	if _, err := parser.Execute([]string{"--lang", "ja", "remove"}); err == nil || err.Error() != "不明なコマンド remove" {
+		t.Errorf("Parser.Execute() error = %v, wantErrStr %q", err, "不明なコマンド remove")
+	}
+	if _, err := other.Execute([]string{"remove"}); err == nil || err.Error() != "unknown command remove" {
+		t.Errorf("Parser.Execute() after --lang error = %v, wantErrStr %q", err, "unknown command remove")
+	}
+
+	if result := parser.Parse([]string{"--lang", "ja", "remove"}); result.Err == nil || result.Err.Error() != "不明なコマンド remove" {
+		t.Errorf("Parser.Parse() error = %v, wantErrStr %q", result.Err, "不明なコマンド remove")
+	}
+	if got := Locale(); got != DefaultLocale {
+		t.Errorf("Locale() after Parser.Parse() = %q, want %q", got, DefaultLocale)
+	}
+}
+
+func TestParser_Execute_Localized_Errors(t *testing.T) {
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}})
+	parser.EnableResponseFiles()
+	_ = parser.AddAlias("a", "b")
+	_ = parser.AddAlias("b", "a")
+
+	type testCase struct {
+		testName      string
+		args          []string
+		wantErrPrefix string
+	}
+	tests := []testCase{
+		{
+			testName:      "Error-RecursiveAlias",
+			args:          []string{"a"},
+			wantErrPrefix: "エイリアスが循環しています: a -> b -> a",
+		},
+		{
+			testName:      "Error-MissingResponseFile",
+			args:          []string{"@missing.txt"},
+			wantErrPrefix: "レスポンスファイル missing.txt を読み込めません: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			SetLocale("ja")
+			defer SetLocale(DefaultLocale)
+			_, err := parser.Execute(tt.args)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErrPrefix) {
+				t.Errorf("Parser.Execute() error = %v, wantErrPrefix %q", err, tt.wantErrPrefix)
+			}
+		})
+	}
+}
+
+func TestCommand_Help_Localized(t *testing.T) {
+	SetLocale("ja")
+	defer SetLocale(DefaultLocale)
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	add := NewCommand("add", nil)
+	_ = add.AddArgument(titleArg)
+
+	want := "使い方: add [引数]\n引数:\n  title <string>"
+	if got := add.Help(); got != want {
+		t.Errorf("Command.Help() = %q, want %q", got, want)
+	}
+}
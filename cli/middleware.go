@@ -0,0 +1,22 @@
+package cli
+
+import "rabbit-todo/cli/param"
+
+// Handler defines the function signature for the invocation of a command's action.
+// It receives the IO of the execution and the parsed arguments and options,
+// and returns the result-string of the action and an error if the execution fails.
+type Handler func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) (string, error)
+
+// Middleware wraps the Handler of a command to add behaviour before and after its action runs.
+// It receives the command being executed and the next Handler in the chain.
+// A middleware can short-circuit the execution by returning an error without calling next.
+type Middleware func(cmd *Command, next Handler) Handler
+
+// chainMiddlewares wraps the handler with the given middlewares.
+// The first middleware becomes the outermost one, so the middlewares run in the order they are given.
+func chainMiddlewares(handler Handler, cmd *Command, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](cmd, handler)
+	}
+	return handler
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	type testCase struct {
+		testName   string
+		parserMWs  []string
+		commandMWs []string
+		stopAt     string
+		args       []string
+		want       string
+		wantTrace  []string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	tests := []testCase{
+		{
+			testName:   "Ok-ParserThenCommandInOrder",
+			parserMWs:  []string{"lock", "timer"},
+			commandMWs: []string{"audit"},
+			args:       []string{"add", "Buy milk"},
+			want:       "added Buy milk",
+			wantTrace: []string{
+				"before lock add Buy milk",
+				"before timer add Buy milk",
+				"before audit add Buy milk",
+				"action",
+				"after audit added Buy milk",
+				"after timer added Buy milk",
+				"after lock added Buy milk",
+			},
+		},
+		{
+			testName:   "Error-ShortCircuit",
+			parserMWs:  []string{"lock", "timer"},
+			commandMWs: []string{"audit"},
+			stopAt:     "timer",
+			args:       []string{"add", "Buy milk"},
+			wantTrace: []string{
+				"before lock add Buy milk",
+				"before timer add Buy milk",
+				"after lock ",
+			},
+			wantErr:    true,
+			wantErrStr: "timer refused add",
+		},
+		{
+			testName:   "Error-ValidationRunsBeforeMiddleware",
+			parserMWs:  []string{"lock"},
+			args:       []string{"add"},
+			wantTrace:  nil,
+			wantErr:    true,
+			wantErrStr: "not enough arguments: actual 0, expected 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var trace []string
+			middlewareGen := func(name string) Middleware {
+				return func(cmd *Command, next Handler) Handler {
+					return func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) (string, error) {
+						trace = append(trace, fmt.Sprintf("before %s %s %s", name, cmd.Name, args["title"].StringVal))
+						if name == tc.stopAt {
+							return "", fmt.Errorf("%s refused %s", name, cmd.Name)
+						}
+						output, err := next(stdio, args, opts)
+						trace = append(trace, fmt.Sprintf("after %s %s", name, output))
+						return output, err
+					}
+				}
+			}
+			action := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+				trace = append(trace, "action")
+				return "added " + args["title"].StringVal, nil
+			}
+
+			titleArg, _ := param.NewArgument("title", param.STRING)
+			command := NewCommand("add", action)
+			_ = command.AddArgument(titleArg)
+			for _, name := range tc.commandMWs {
+				command.Use(middlewareGen(name))
+			}
+			parser := NewParser()
+			for _, name := range tc.parserMWs {
+				parser.Use(middlewareGen(name))
+			}
+			_ = parser.AddCommand(command)
+
+			got, err := parser.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+			if !reflect.DeepEqual(trace, tc.wantTrace) {
+				t.Errorf("middleware trace = \n%s\nwant\n%s", strings.Join(trace, "\n"), strings.Join(tc.wantTrace, "\n"))
+			}
+		})
+	}
+}
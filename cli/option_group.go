@@ -0,0 +1,113 @@
+package cli
+
+import "strings"
+
+type groupKind int
+
+const (
+	mutuallyExclusive groupKind = iota
+	requiredTogether
+	dependency
+)
+
+// optionGroup is a constraint between options of a command.
+// For a dependency group, the first name is the dependent option and the rest are the options it requires.
+// The names are the long names of the options, or the `--no-<name>` form of a negatable flag,
+// which is only present when the flag is given in that form.
+type optionGroup struct {
+	kind  groupKind
+	names []string
+}
+
+// AddMutuallyExclusive declares that at most one of the named options may be given.
+// It returns an error if fewer than two names are given or an option does not exist in the command.
+func (c *Command) AddMutuallyExclusive(names ...string) error {
+	return c.addOptionGroup(mutuallyExclusive, names)
+}
+
+// AddRequiredTogether declares that the named options must be given all together or not at all.
+// It returns an error if fewer than two names are given or an option does not exist in the command.
+func (c *Command) AddRequiredTogether(names ...string) error {
+	return c.addOptionGroup(requiredTogether, names)
+}
+
+// AddDependency declares that the option with the given name may only be given
+// when all the required options are given too.
+// It returns an error if no required option is given or an option does not exist in the command.
+func (c *Command) AddDependency(name string, required ...string) error {
+	return c.addOptionGroup(dependency, append([]string{name}, required...))
+}
+
+// addOptionGroup adds a group of the named options, which may be given by their short form.
+func (c *Command) addOptionGroup(kind groupKind, names []string) error {
+	if len(names) < 2 {
+		return Errorf("option group requires at least two options")
+	}
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		option, negated := lookupOption(c.options, name)
+		switch {
+		case option == nil:
+			return Errorf("unknown option %s in option group", name)
+		case negated:
+			resolved = append(resolved, option.NegatedName())
+		default:
+			resolved = append(resolved, option.Name)
+		}
+	}
+	c.groups = append(c.groups, optionGroup{kind: kind, names: resolved})
+	return nil
+}
+
+// validateOptionGroups checks the options given in the input parameters against the option groups of the command.
+// The keys of given and negatedGiven are the option names without the `--` prefix,
+// and negatedGiven tells whether a negatable flag was given in its `--no-<name>` form.
+// It returns an error describing the first violated group.
+func (c *Command) validateOptionGroups(given map[string]bool, negatedGiven map[string]bool) error {
+	isGiven := func(name string) bool {
+		option, negated := lookupOption(c.options, name)
+		key := strings.TrimPrefix(option.Name, optionPrefix)
+		return given[key] && negatedGiven[key] == negated
+	}
+	for _, group := range c.groups {
+		var present, missing []string
+		for _, name := range group.names {
+			if isGiven(name) {
+				present = append(present, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+
+		switch group.kind {
+		case mutuallyExclusive:
+			if len(present) > 1 {
+				return Errorf("options %s are mutually exclusive", strings.Join(present, ", "))
+			}
+		case requiredTogether:
+			if len(present) > 0 && len(missing) > 0 {
+				return Errorf("options %s must be used together: missing %s",
+					strings.Join(group.names, ", "), strings.Join(missing, ", "))
+			}
+		case dependency:
+			if isGiven(group.names[0]) && len(missing) > 0 {
+				return Errorf("option %s requires %s", group.names[0], strings.Join(missing, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the description of the option group shown in the usage of the command.
+func (g optionGroup) String() string {
+	switch g.kind {
+	case mutuallyExclusive:
+		return Sprintf("%s (mutually exclusive)", strings.Join(g.names, " | "))
+	case requiredTogether:
+		return Sprintf("%s (required together)", strings.Join(g.names, " & "))
+	case dependency:
+		return Sprintf("%s requires %s", g.names[0], strings.Join(g.names[1:], ", "))
+	default:
+		return ""
+	}
+}
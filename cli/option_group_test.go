@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"rabbit-todo/cli/param"
+	"testing"
+)
+
+func TestCommand_AddOptionGroup(t *testing.T) {
+	type testCase struct {
+		testName   string
+		add        func(c *Command) error
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-MutuallyExclusive",
+			add:      func(c *Command) error { return c.AddMutuallyExclusive("--done", "--pending") },
+		},
+		{
+			testName: "Ok-Dependency",
+			add:      func(c *Command) error { return c.AddDependency("--remind", "--due") },
+		},
+		{
+			testName:   "Error-SingleOption",
+			add:        func(c *Command) error { return c.AddRequiredTogether("--done") },
+			wantErr:    true,
+			wantErrStr: "option group requires at least two options",
+		},
+		{
+			testName:   "Error-UnknownOption",
+			add:        func(c *Command) error { return c.AddMutuallyExclusive("--done", "--unknown") },
+			wantErr:    true,
+			wantErrStr: "unknown option --unknown in option group",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			command := NewCommand("list", nil)
+			for _, name := range []string{"--done", "--pending", "--remind", "--due"} {
+				option, _ := param.NewFlagOption(name)
+				_ = command.AddOption(option)
+			}
+			err := tc.add(&command)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.addOptionGroup() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr && err.Error() != tc.wantErrStr {
+				t.Errorf("Command.addOptionGroup() error = %q, wantErrStr %q", err, tc.wantErrStr)
+			}
+		})
+	}
+}
+
+func TestCommand_Execute_With_OptionGroups(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "ok", nil
+	}
+
+	type testCase struct {
+		testName   string
+		args       []string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	command := NewCommand("list", testAction)
+	doneOption, _ := param.NewFlagOption("--done")
+	pendingOption, _ := param.NewFlagOption("--pending")
+	fromOption, _ := param.NewOption("--from", param.STRING)
+	toOption, _ := param.NewOption("--to", param.STRING)
+	remindOption, _ := param.NewOption("--remind", param.STRING)
+	dueOption, _ := param.NewOption("--due", param.STRING)
+	archiveOption, _ := param.NewNegatableFlagOption("--archive")
+	_ = pendingOption.SetShort("-p")
+	for _, option := range []*param.Option{doneOption, pendingOption, fromOption, toOption, remindOption, dueOption, archiveOption} {
+		_ = command.AddOption(option)
+	}
+	_ = command.AddMutuallyExclusive("--done", "-p")
+	_ = command.AddRequiredTogether("--from", "--to")
+	_ = command.AddDependency("--remind", "--due")
+	_ = command.AddMutuallyExclusive("--due", "--no-archive")
+
+	tests := []testCase{
+		{
+			testName: "Ok-NoOptions",
+			args:     []string{},
+		},
+		{
+			testName: "Ok-AllGroupsSatisfied",
+			args:     []string{"--done", "--from", "mon", "--to", "fri", "--remind", "1h", "--due", "fri"},
+		},
+		{
+			testName: "Ok-DependencyWithoutDependent",
+			args:     []string{"--due", "fri"},
+		},
+		{
+			testName:   "Error-MutuallyExclusive",
+			args:       []string{"--pending", "--done"},
+			wantErr:    true,
+			wantErrStr: "options --done, --pending are mutually exclusive",
+		},
+		{
+			testName: "Ok-PositiveFormOfNegatedName",
+			args:     []string{"--due", "fri", "--archive"},
+		},
+		{
+			testName:   "Error-NegatedName",
+			args:       []string{"--due", "fri", "--no-archive"},
+			wantErr:    true,
+			wantErrStr: "options --due, --no-archive are mutually exclusive",
+		},
+		{
+			testName:   "Error-RequiredTogether",
+			args:       []string{"--to", "fri"},
+			wantErr:    true,
+			wantErrStr: "options --from, --to must be used together: missing --from",
+		},
+		{
+			testName:   "Error-Dependency",
+			args:       []string{"--remind", "1h"},
+			wantErr:    true,
+			wantErrStr: "option --remind requires --due",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			_, err := command.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Command.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr && err.Error() != tc.wantErrStr {
+				t.Errorf("Command.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+			}
+		})
+	}
+
+	t.Run("Ok-Usage", func(t *testing.T) {
+		want := "Usage: list [options]\n" +
+			"Option constraints:\n" +
+			"  --done | --pending (mutually exclusive)\n" +
+			"  --from & --to (required together)\n" +
+			"  --remind requires --due\n" +
+			"  --due | --no-archive (mutually exclusive)"
+		if got := command.Usage(); got != want {
+			t.Errorf("Command.Usage() = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,424 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"rabbit-todo/cli/param"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Output formats accepted by the --output option.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+	OutputCSV   = "csv"
+	OutputYAML  = "yaml"
+)
+
+const (
+	outputOptionName  = "--output"
+	columnsOptionName = "--columns"
+	descendingPrefix  = "-"
+)
+
+// Record is one row of structured output, keyed by column name.
+type Record map[string]interface{}
+
+// Table is the structured result of a RecordAction.
+// Columns are the columns shown by default, in order; if it is empty, all the keys of the records are shown in sorted order.
+// SortBy hints the columns the records are sorted by, in order of precedence;
+// a column name prefixed with "-" sorts in descending order.
+type Table struct {
+	Columns []string
+	Records []Record
+	SortBy  []string
+}
+
+// RecordAction defines the function signature for actions that return structured records
+// instead of a pre-rendered string, so that the user can choose how they are rendered.
+type RecordAction func(args map[string]param.Value, opts map[string]param.Value) (*Table, error)
+
+// NewRecordCommand constructs a new Command object with the given name and record action.
+// The table returned by the action is rendered to the output of the execution in the format
+// of the --output option and with the columns of the --columns option, if they are given.
+func NewRecordCommand(name string, action RecordAction) Command {
+	return NewStreamCommand(name, func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		table, err := action(args, opts)
+		if err != nil {
+			return err
+		}
+		format := opts[strings.TrimPrefix(outputOptionName, optionPrefix)].StringVal
+		var columns []string
+		for _, column := range opts[strings.TrimPrefix(columnsOptionName, optionPrefix)].ListVal {
+			columns = append(columns, column.StringVal)
+		}
+		return RenderTable(stdio.Out, table, format, columns)
+	})
+}
+
+// EnableOutputFormats registers the global options --output, which selects the format that
+// record commands render their tables in, and --columns, which selects the columns shown, as in `--columns id,title,due`.
+func (p *Parser) EnableOutputFormats() error {
+	outputOption, err := param.NewOption(outputOptionName, param.STRING)
+	if err != nil {
+		return err
+	}
+	outputOption.Description = "output format: table, json, jsonl, csv or yaml"
+	outputOption.AddValidators(param.OneOf(OutputTable, OutputJSON, OutputJSONL, OutputCSV, OutputYAML))
+	outputOption.Complete = func(partial string, parsed map[string]param.Value) []param.Candidate {
+		return []param.Candidate{{Value: OutputTable}, {Value: OutputJSON}, {Value: OutputJSONL}, {Value: OutputCSV}, {Value: OutputYAML}}
+	}
+	columnsOption, err := param.NewListOption(columnsOptionName, param.STRING)
+	if err != nil {
+		return err
+	}
+	columnsOption.Description = "comma-separated columns to show"
+	if err := p.AddOption(outputOption); err != nil {
+		return err
+	}
+	return p.AddOption(columnsOption)
+}
+
+// RenderTable writes the records of the table to w in the given format, which defaults to OutputTable.
+// Only the given columns are written if there are any, and the records are sorted as hinted by the table.
+// It returns an error if the format or one of the columns is unknown.
+func RenderTable(w io.Writer, table *Table, format string, columns []string) error {
+	if table == nil {
+		table = &Table{}
+	}
+	if len(columns) == 0 {
+		columns = table.columns()
+	} else {
+		known := make(map[string]bool)
+		for _, column := range table.columns() {
+			known[column] = true
+		}
+		for _, column := range columns {
+			if !known[column] {
+				return Errorf("unknown column %s", column)
+			}
+		}
+	}
+	records := table.sortedRecords()
+
+	switch format {
+	case "", OutputTable:
+		return renderAligned(w, columns, records)
+	case OutputJSON:
+		return renderJSON(w, columns, records)
+	case OutputJSONL:
+		return renderJSONLines(w, columns, records)
+	case OutputCSV:
+		return renderCSV(w, columns, records)
+	case OutputYAML:
+		return renderYAML(w, columns, records)
+	default:
+		return Errorf("unknown output format %s", format)
+	}
+}
+
+// columns returns the columns of the table, or the sorted keys of its records if it declares none.
+func (t *Table) columns() []string {
+	if len(t.Columns) > 0 {
+		return t.Columns
+	}
+	seen := make(map[string]bool)
+	var columns []string
+	for _, record := range t.Records {
+		for key := range record {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// sortedRecords returns a copy of the records sorted by the SortBy columns.
+// Records that compare equal keep their order.
+func (t *Table) sortedRecords() []Record {
+	records := append([]Record{}, t.Records...)
+	if len(t.SortBy) == 0 {
+		return records
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, column := range t.SortBy {
+			name, descending := strings.CutPrefix(column, descendingPrefix)
+			cmp := compareValues(records[i][name], records[j][name])
+			if cmp != 0 {
+				return (cmp < 0) != descending
+			}
+		}
+		return false
+	})
+	return records
+}
+
+// compareValues orders missing values first, numbers numerically, times chronologically
+// and other values by their text.
+func compareValues(a interface{}, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(formatCell(a), formatCell(b))
+}
+
+// toFloat converts a numeric value to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// formatCell formats a value for the aligned table and CSV formats.
+// A missing value is empty and a time is formatted as RFC 3339.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// renderAligned writes the records as a table with a header of upper-cased column names
+// and the cells of each column aligned.
+func renderAligned(w io.Writer, columns []string, records []Record) error {
+	rows := make([][]string, 0, len(records)+1)
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	rows = append(rows, header)
+	for _, record := range records {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, formatCell(record[column]))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString(helpColumnGap)
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalRecord encodes the columns of the record as a JSON object with the keys in column order.
+func marshalRecord(columns []string, record Record) (string, error) {
+	var builder strings.Builder
+	builder.WriteString("{")
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return "", err
+		}
+		value, err := json.Marshal(record[column])
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.Write(key)
+		builder.WriteString(":")
+		builder.Write(value)
+	}
+	builder.WriteString("}")
+	return builder.String(), nil
+}
+
+// renderJSON writes the records as an indented JSON array of objects.
+func renderJSON(w io.Writer, columns []string, records []Record) error {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for i, record := range records {
+		object, err := marshalRecord(columns, record)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(object)
+	}
+	builder.WriteString("]")
+
+	var indented strings.Builder
+	encoder := json.NewEncoder(&indented)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(json.RawMessage(builder.String())); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, indented.String())
+	return err
+}
+
+// renderJSONLines writes each record as a JSON object on its own line.
+func renderJSONLines(w io.Writer, columns []string, records []Record) error {
+	for _, record := range records {
+		object, err := marshalRecord(columns, record)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderCSV writes the records as CSV with a header of column names.
+func renderCSV(w io.Writer, columns []string, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, formatCell(record[column]))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// renderYAML writes the records as a YAML sequence of mappings.
+// Values that are not scalars are written in the JSON flow style, which is valid YAML.
+func renderYAML(w io.Writer, columns []string, records []Record) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+	for _, record := range records {
+		for i, column := range columns {
+			value, err := yamlValue(record[column])
+			if err != nil {
+				return err
+			}
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			if _, err := fmt.Fprintf(w, "%s%s: %s\n", prefix, yamlString(column), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yamlValue formats a value as a YAML scalar or flow collection.
+func yamlValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return yamlString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	}
+	if _, ok := toFloat(value); ok {
+		return fmt.Sprint(value), nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// yamlString returns the string as a plain YAML scalar if it would be read back as the same string,
+// or else as a double-quoted scalar. Strings that start like a number, such as dates, are always quoted.
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") ||
+		strings.ContainsAny(s[:1], "-?.+0123456789") || isYAMLKeyword(s) {
+		encoded, _ := json.Marshal(s)
+		return string(encoded)
+	}
+	return s
+}
+
+// isYAMLKeyword reports whether the plain scalar would be read as a boolean or null.
+func isYAMLKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"rabbit-todo/cli/param"
+	"strings"
+	"testing"
+)
+
+func TestRenderTable(t *testing.T) {
+	table := &Table{
+		Columns: []string{"id", "title", "due"},
+		Records: []Record{
+			{"id": 2, "title": "call bob", "due": "2026-10-21"},
+			{"id": 10, "title": "buy milk, eggs", "due": nil},
+			{"id": 1, "title": "yes", "due": "2026-10-20"},
+		},
+		SortBy: []string{"id"},
+	}
+
+	type testCase struct {
+		testName   string
+		format     string
+		columns    []string
+		want       string
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Table",
+			format:   "",
+			want: "ID  TITLE           DUE\n" +
+				"1   yes             2026-10-20\n" +
+				"2   call bob        2026-10-21\n" +
+				"10  buy milk, eggs\n",
+		},
+		{
+			testName: "Ok-Columns",
+			format:   OutputTable,
+			columns:  []string{"title", "id"},
+			want: "TITLE           ID\n" +
+				"yes             1\n" +
+				"call bob        2\n" +
+				"buy milk, eggs  10\n",
+		},
+		{
+			testName: "Ok-JSON",
+			format:   OutputJSON,
+			columns:  []string{"id", "due"},
+			want: "[\n" +
+				"  {\n    \"id\": 1,\n    \"due\": \"2026-10-20\"\n  },\n" +
+				"  {\n    \"id\": 2,\n    \"due\": \"2026-10-21\"\n  },\n" +
+				"  {\n    \"id\": 10,\n    \"due\": null\n  }\n" +
+				"]\n",
+		},
+		{
+			testName: "Ok-JSONLines",
+			format:   OutputJSONL,
+			want: "{\"id\":1,\"title\":\"yes\",\"due\":\"2026-10-20\"}\n" +
+				"{\"id\":2,\"title\":\"call bob\",\"due\":\"2026-10-21\"}\n" +
+				"{\"id\":10,\"title\":\"buy milk, eggs\",\"due\":null}\n",
+		},
+		{
+			testName: "Ok-CSV",
+			format:   OutputCSV,
+			want: "id,title,due\n" +
+				"1,yes,2026-10-20\n" +
+				"2,call bob,2026-10-21\n" +
+				"10,\"buy milk, eggs\",\n",
+		},
+		{
+			testName: "Ok-YAML",
+			format:   OutputYAML,
+			want: "- id: 1\n  title: \"yes\"\n  due: \"2026-10-20\"\n" +
+				"- id: 2\n  title: call bob\n  due: \"2026-10-21\"\n" +
+				"- id: 10\n  title: \"buy milk, eggs\"\n  due: null\n",
+		},
+		{
+			testName:   "Error-UnknownColumn",
+			format:     OutputTable,
+			columns:    []string{"owner"},
+			wantErrStr: "unknown column owner",
+		},
+		{
+			testName:   "Error-UnknownFormat",
+			format:     "xml",
+			wantErrStr: "unknown output format xml",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var out bytes.Buffer
+			err := RenderTable(&out, table, tc.format, tc.columns)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("RenderTable() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTable() error = %v", err)
+			}
+			if got := out.String(); got != tc.want {
+				t.Errorf("RenderTable() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTable_sortedRecords(t *testing.T) {
+	table := &Table{
+		Records: []Record{
+			{"project": "home", "priority": 1},
+			{"project": "work", "priority": 3},
+			{"project": "home", "priority": 2},
+			{"priority": 5},
+		},
+		SortBy: []string{"project", "-priority"},
+	}
+	var got []interface{}
+	for _, record := range table.sortedRecords() {
+		got = append(got, record["priority"])
+	}
+	want := []interface{}{5, 2, 1, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Table.sortedRecords() priorities = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParser_Execute_With_OutputFormats(t *testing.T) {
+	list := NewRecordCommand("list", func(args map[string]param.Value, opts map[string]param.Value) (*Table, error) {
+		return &Table{
+			Columns: []string{"id", "title"},
+			Records: []Record{{"id": 1, "title": "buy milk"}},
+		}, nil
+	})
+
+	var stdout bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}})
+	if err := parser.EnableOutputFormats(); err != nil {
+		t.Fatalf("Parser.EnableOutputFormats() error = %v", err)
+	}
+	_ = parser.AddCommand(list)
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Default",
+			args:     []string{"list"},
+			want:     "ID  TITLE\n1   buy milk\n",
+		},
+		{
+			testName: "Ok-OutputAndColumns",
+			args:     []string{"--output", "jsonl", "list", "--columns", "title"},
+			want:     "{\"title\":\"buy milk\"}\n",
+		},
+		{
+			testName:   "Error-InvalidFormat",
+			args:       []string{"list", "--output", "xml"},
+			wantErrStr: "invalid option \"--output\": one-of[table json jsonl csv yaml]: xml is not one of [table json jsonl csv yaml]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			stdout.Reset()
+			_, err := parser.Execute(tc.args)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parser.Execute() error = %v", err)
+			}
+			if got := stdout.String(); got != tc.want {
+				t.Errorf("Parser.Execute() stdout = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"rabbit-todo/cli/param"
+	"rabbit-todo/cli/style"
+	"strings"
+)
+
+const (
+	// DefaultPager is the pager command used when neither the config nor $PAGER names one.
+	DefaultPager        = "less -FRX"
+	noPagerOptionName   = "--no-pager"
+	pagerConfigKey      = "pager"
+	pagerEnvironmentVar = "PAGER"
+)
+
+// EnablePager makes the parser pipe the output of a command through a pager when it is written to a terminal
+// and has more lines than fit on it. The pager command is taken from the "pager" key of the config,
+// then the PAGER environment variable, and is DefaultPager if neither is set.
+// It registers the global flag --no-pager that turns paging off, which can also be set in the config as "no-pager".
+func (p *Parser) EnablePager() error {
+	noPagerOption, err := param.NewFlagOption(noPagerOptionName)
+	if err != nil {
+		return err
+	}
+	noPagerOption.Description = "do not pipe the output through a pager"
+	if err := p.AddOption(noPagerOption); err != nil {
+		return err
+	}
+	p.paging = true
+	return nil
+}
+
+// pagerFor returns a pagerWriter for the output of stdio, or nil if the output should not be paged
+// because paging is disabled or turned off, the output is not a terminal,
+// or stdio belongs to a command line executed by another command.
+func (p *Parser) pagerFor(stdio *IO, opts map[string]param.Value) *pagerWriter {
+	if !p.paging || stdio.nested || opts[strings.TrimPrefix(noPagerOptionName, optionPrefix)].BoolVal {
+		return nil
+	}
+	file, ok := stdio.Out.(*os.File)
+	if !ok || !isTerminal(file) {
+		return nil
+	}
+	command, err := Tokenize(p.pagerCommand())
+	if err != nil || len(command) == 0 {
+		return nil
+	}
+	return newPagerWriter(stdio.Out, stdio.Err, terminalHeight(stdio.Out), command)
+}
+
+// pagerCommand returns the command line of the pager.
+func (p *Parser) pagerCommand() string {
+	if command := p.config[pagerConfigKey]; command != "" {
+		return command
+	}
+	if command := os.Getenv(pagerEnvironmentVar); command != "" {
+		return command
+	}
+	return DefaultPager
+}
+
+// pagedIO returns a copy of stdio that writes its output to the pager.
+// The color mode is resolved against the terminal first, since the pager is not a terminal itself.
+func pagedIO(stdio *IO, pager *pagerWriter) *IO {
+	paged := *stdio
+	if paged.Color == style.Auto {
+		paged.Color = style.Never
+		if stdio.Style().Enabled() {
+			paged.Color = style.Always
+		}
+	}
+	paged.Out = pager
+	return &paged
+}
+
+// pagerWriter holds back the output until it has more lines than the height of the terminal,
+// then starts the pager and streams the output to it. Output that fits on the terminal is written
+// to it directly when the writer is closed.
+type pagerWriter struct {
+	out     io.Writer
+	errOut  io.Writer
+	height  int
+	command []string
+	buffer  bytes.Buffer
+	lines   int
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	direct  bool
+}
+
+func newPagerWriter(out io.Writer, errOut io.Writer, height int, command []string) *pagerWriter {
+	return &pagerWriter{out: out, errOut: errOut, height: height, command: command}
+}
+
+// Write buffers the output until it is longer than the terminal, then writes it to the pager.
+// Output written after the user has quit the pager is discarded.
+func (w *pagerWriter) Write(b []byte) (int, error) {
+	switch {
+	case w.direct:
+		return w.out.Write(b)
+	case w.stdin != nil:
+		_, _ = w.stdin.Write(b)
+		return len(b), nil
+	}
+	w.buffer.Write(b)
+	w.lines += bytes.Count(b, []byte("\n"))
+	if w.lines > w.height {
+		if err := w.start(); err != nil {
+			w.direct = true
+			_, err := w.out.Write(w.buffer.Bytes())
+			w.buffer.Reset()
+			return len(b), err
+		}
+	}
+	return len(b), nil
+}
+
+// start runs the pager and writes the buffered output to it.
+func (w *pagerWriter) start() error {
+	cmd := exec.Command(w.command[0], w.command[1:]...)
+	cmd.Stdout = w.out
+	cmd.Stderr = w.errOut
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	w.cmd = cmd
+	w.stdin = stdin
+	_, _ = stdin.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return nil
+}
+
+// Close writes the output that fit on the terminal, or waits for the user to quit the pager.
+func (w *pagerWriter) Close() error {
+	if w.stdin == nil {
+		_, err := w.out.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+		return err
+	}
+	_ = w.stdin.Close()
+	return w.cmd.Wait()
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"rabbit-todo/cli/param"
+	"strings"
+	"testing"
+)
+
+func TestPagerWriter(t *testing.T) {
+	type testCase struct {
+		testName string
+		command  []string
+		lines    int
+		want     string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-FitsOnTerminal",
+			command:  []string{"sed", "s/^/paged: /"},
+			lines:    3,
+			want:     "line 1\nline 2\nline 3\n",
+		},
+		{
+			testName: "Ok-Paged",
+			command:  []string{"sed", "s/^/paged: /"},
+			lines:    4,
+			want:     "paged: line 1\npaged: line 2\npaged: line 3\npaged: line 4\n",
+		},
+		{
+			testName: "Ok-PagerNotFound",
+			command:  []string{"rabbit-todo-missing-pager"},
+			lines:    5,
+			want:     "line 1\nline 2\nline 3\nline 4\nline 5\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			pager := newPagerWriter(&stdout, &stderr, 3, tc.command)
+			for i := 1; i <= tc.lines; i++ {
+				if _, err := fmt.Fprintf(pager, "line %d\n", i); err != nil {
+					t.Fatalf("pagerWriter.Write() error = %v", err)
+				}
+			}
+			if err := pager.Close(); err != nil {
+				t.Fatalf("pagerWriter.Close() error = %v", err)
+			}
+			if got := stdout.String(); got != tc.want {
+				t.Errorf("pager output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_pagerCommand(t *testing.T) {
+	type testCase struct {
+		testName string
+		config   map[string]string
+		env      string
+		want     string
+	}
+	tests := []testCase{
+		{testName: "Ok-Default", want: DefaultPager},
+		{testName: "Ok-Env", env: "more", want: "more"},
+		{testName: "Ok-ConfigOverEnv", config: map[string]string{"pager": "less -S"}, env: "more", want: "less -S"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Setenv("PAGER", tc.env)
+			parser := NewParser()
+			parser.SetConfig(tc.config)
+			if got := parser.pagerCommand(); got != tc.want {
+				t.Errorf("Parser.pagerCommand() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_Execute_With_Pager(t *testing.T) {
+	list := NewCommand("list", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return strings.Repeat("task\n", 100) + "end", nil
+	})
+
+	var stdout bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}})
+	if err := parser.EnablePager(); err != nil {
+		t.Fatalf("Parser.EnablePager() error = %v", err)
+	}
+	_ = parser.AddCommand(list)
+
+	for _, args := range [][]string{{"list"}, {"--no-pager", "list"}} {
+		got, err := parser.Execute(args)
+		if err != nil {
+			t.Fatalf("Parser.Execute(%q) error = %v", args, err)
+		}
+		if want := strings.Repeat("task\n", 100) + "end"; got != want {
+			t.Errorf("Parser.Execute(%q) = %q, want the output returned when it is not a terminal", args, got)
+		}
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Parser.Execute() stdout = %q, want nothing", stdout.String())
+	}
+}
+
+func TestParser_Execute_Nested_NotPaged(t *testing.T) {
+	var inner *IO
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader("list\n"), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}})
+	if err := parser.EnablePager(); err != nil {
+		t.Fatalf("Parser.EnablePager() error = %v", err)
+	}
+	_ = parser.AddCommand(NewStreamCommand("list", func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		inner = stdio
+		return nil
+	}))
+	batch := NewBatchCommand(parser, nil)
+	if !batch.noPager {
+		t.Errorf("NewBatchCommand() noPager = false, want true")
+	}
+	_ = parser.AddCommand(batch)
+
+	if _, err := parser.Execute([]string{"batch", "-"}); err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if inner == nil || !inner.nested {
+		t.Fatalf("IO of a command run by batch is not nested")
+	}
+	if pager := parser.pagerFor(inner, nil); pager != nil {
+		t.Errorf("Parser.pagerFor() = %v, want nil for a nested IO", pager)
+	}
+}
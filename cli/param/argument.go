@@ -1,25 +1,47 @@
 package param
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
+// Argument is a positional parameter of a command.
+// Description, Default and Choices are shown to the user when the argument is prompted for,
+// and a value outside of Choices is rejected when Choices is not empty.
+// Complete suggests values for the argument on the command line; the Choices are suggested if it is nil.
 type Argument struct {
-	Name string
-	Type Type
+	Name        string
+	Type        Type
+	Validators  []Validator
+	Description string
+	Default     *Value
+	Choices     []string
+	Complete    CompleteFunc
 }
 
 func NewArgument(name string, tp Type) (*Argument, error) {
 	if len(name) == 0 {
-		return nil, fmt.Errorf("name must not be empty")
+		return nil, errorf("name must not be empty")
 	}
 
 	if strings.HasPrefix(name, "--") {
-		return nil, fmt.Errorf("name must not start with '--'")
+		return nil, errorf("name must not start with '--'")
 	}
 	return &Argument{
 		Name: name,
 		Type: tp,
 	}, nil
 }
+
+// AddValidators appends validators that are run against the value of the argument after type conversion.
+func (a *Argument) AddValidators(validators ...Validator) {
+	a.Validators = append(a.Validators, validators...)
+}
+
+// Validate checks the given value against the choices of the argument,
+// then runs the validators of the argument in the order they were added.
+// It returns an error naming the argument and the failing rule.
+func (a *Argument) Validate(value Value) error {
+	validators := a.Validators
+	if len(a.Choices) > 0 {
+		validators = append([]Validator{OneOf(a.Choices...)}, validators...)
+	}
+	return validate("argument", a.Name, validators, value)
+}
@@ -95,3 +95,17 @@ func TestNewArgument(t *testing.T) {
 		})
 	}
 }
+
+func TestArgument_Validate_With_Choices(t *testing.T) {
+	arg, _ := NewArgument("list", STRING)
+	arg.Choices = []string{"work", "home"}
+
+	if err := arg.Validate(*NewStringParameterPtr("home")); err != nil {
+		t.Errorf("Argument.Validate() error = %v, want nil", err)
+	}
+	err := arg.Validate(*NewStringParameterPtr("office"))
+	wantErrStr := "invalid argument \"list\": one-of[work home]: office is not one of [work home]"
+	if err == nil || err.Error() != wantErrStr {
+		t.Errorf("Argument.Validate() error = %v, wantErrStr %q", err, wantErrStr)
+	}
+}
@@ -0,0 +1,12 @@
+package param
+
+// Candidate is a suggested value for a parameter, with an optional description shown next to it.
+type Candidate struct {
+	Value       string
+	Description string
+}
+
+// CompleteFunc returns the candidates for the value of a parameter that the user is typing.
+// The partial is the part of the value typed so far, and parsed holds the values of the arguments
+// and options that precede it on the command line, by argument name or option name without the `--` prefix.
+type CompleteFunc func(partial string, parsed map[string]Value) []Candidate
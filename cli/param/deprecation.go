@@ -0,0 +1,28 @@
+package param
+
+import "fmt"
+
+// Deprecation marks a command or option that is still accepted but should no longer be used.
+// Replacement names what to use instead, and Message gives further details; both are optional.
+type Deprecation struct {
+	Replacement string
+	Message     string
+}
+
+// Warning generates the message shown to the user when the deprecated command or option is used.
+// The kind is "command" or "option", and name is the name of the command or option.
+func (d *Deprecation) Warning(kind string, name string) string {
+	return sprintf("warning: %s %s is %s", translate(kind), name, d.Note())
+}
+
+// Note describes the deprecation with its replacement and message, as in "deprecated, use remove instead".
+func (d *Deprecation) Note() string {
+	note := translate("deprecated")
+	if d.Replacement != "" {
+		note += sprintf(", use %s instead", d.Replacement)
+	}
+	if d.Message != "" {
+		note += fmt.Sprintf(": %s", d.Message)
+	}
+	return note
+}
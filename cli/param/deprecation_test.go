@@ -0,0 +1,35 @@
+package param
+
+import "testing"
+
+func TestDeprecation_Warning(t *testing.T) {
+	type testCase struct {
+		testName    string
+		deprecation Deprecation
+		want        string
+	}
+	tests := []testCase{
+		{
+			testName:    "Ok-NoDetails",
+			deprecation: Deprecation{},
+			want:        "warning: command rm is deprecated",
+		},
+		{
+			testName:    "Ok-Replacement",
+			deprecation: Deprecation{Replacement: "remove"},
+			want:        "warning: command rm is deprecated, use remove instead",
+		},
+		{
+			testName:    "Ok-ReplacementAndMessage",
+			deprecation: Deprecation{Replacement: "remove", Message: "rm will be dropped in v2"},
+			want:        "warning: command rm is deprecated, use remove instead: rm will be dropped in v2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := tc.deprecation.Warning("command", "rm"); got != tc.want {
+				t.Errorf("Deprecation.Warning() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
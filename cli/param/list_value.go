@@ -0,0 +1,68 @@
+package param
+
+import "strings"
+
+const (
+	// DefaultListSeparator separates the elements of a LIST value, as in "work,home".
+	DefaultListSeparator = ","
+	listEscape           = '\\'
+)
+
+// ToListValue splits the value at each separator into elements converted to elemType,
+// as in "1,2,3" for a list of INT elements.
+// A separator or backslash preceded by a backslash is part of the element, as in `a\,b`.
+// An empty value is an empty list.
+// A conversion error names the position of the element, starting at 1, as in "element 2: cannot convert x to Integer".
+func ToListValue(value string, elemType Type, separator string) (*Value, error) {
+	if separator == "" {
+		separator = DefaultListSeparator
+	}
+	elements, err := splitList(value, separator)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]Value, 0, len(elements))
+	for i, element := range elements {
+		converted, err := ToParameterValue(element, elemType)
+		if err != nil {
+			return nil, errorf("element %d: %w", i+1, err)
+		}
+		values = append(values, *converted)
+	}
+	return NewListParameterPtr(values), nil
+}
+
+// splitList splits the value at each separator that is not escaped with a backslash,
+// and removes the escaping backslashes.
+func splitList(value string, separator string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var elements []string
+	var element strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == listEscape:
+			rest := value[i+1:]
+			switch {
+			case strings.HasPrefix(rest, separator):
+				element.WriteString(separator)
+				i += len(separator)
+			case strings.HasPrefix(rest, string(listEscape)):
+				element.WriteByte(listEscape)
+				i++
+			case rest == "":
+				return nil, errorf("invalid list \"%s\": trailing backslash", value)
+			default:
+				element.WriteByte(listEscape)
+			}
+		case strings.HasPrefix(value[i:], separator):
+			elements = append(elements, element.String())
+			element.Reset()
+			i += len(separator) - 1
+		default:
+			element.WriteByte(value[i])
+		}
+	}
+	return append(elements, element.String()), nil
+}
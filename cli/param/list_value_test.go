@@ -0,0 +1,97 @@
+package param
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToListValue(t *testing.T) {
+	type inputType struct {
+		value     string
+		elemType  Type
+		separator string
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		want       *Value
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Strings",
+			input:    inputType{value: "work,home", elemType: STRING, separator: ","},
+			want:     NewListParameterPtr([]Value{*NewStringParameterPtr("work"), *NewStringParameterPtr("home")}),
+		},
+		{
+			testName: "Ok-Integers",
+			input:    inputType{value: "1,2,3", elemType: INT, separator: ","},
+			want: NewListParameterPtr([]Value{
+				*NewIntegerParameterPtr(1), *NewIntegerParameterPtr(2), *NewIntegerParameterPtr(3),
+			}),
+		},
+		{
+			testName: "Ok-CustomSeparator",
+			input:    inputType{value: "a b;c", elemType: STRING, separator: ";"},
+			want:     NewListParameterPtr([]Value{*NewStringParameterPtr("a b"), *NewStringParameterPtr("c")}),
+		},
+		{
+			testName: "Ok-DefaultSeparator",
+			input:    inputType{value: "a,b", elemType: STRING},
+			want:     NewListParameterPtr([]Value{*NewStringParameterPtr("a"), *NewStringParameterPtr("b")}),
+		},
+		{
+			testName: "Ok-EscapedSeparator",
+			input:    inputType{value: `a\,b,c`, elemType: STRING, separator: ","},
+			want:     NewListParameterPtr([]Value{*NewStringParameterPtr("a,b"), *NewStringParameterPtr("c")}),
+		},
+		{
+			testName: "Ok-EscapedBackslash",
+			input:    inputType{value: `a\\,b\c`, elemType: STRING, separator: ","},
+			want:     NewListParameterPtr([]Value{*NewStringParameterPtr(`a\`), *NewStringParameterPtr(`b\c`)}),
+		},
+		{
+			testName: "Ok-Empty",
+			input:    inputType{value: "", elemType: INT, separator: ","},
+			want:     NewListParameterPtr([]Value{}),
+		},
+		{
+			testName:   "Error-ElementConversion",
+			input:      inputType{value: "1,x", elemType: INT, separator: ","},
+			wantErrStr: "element 2: cannot convert x to Integer",
+		},
+		{
+			testName:   "Error-TrailingBackslash",
+			input:      inputType{value: `a,b\`, elemType: STRING, separator: ","},
+			wantErrStr: `invalid list "a,b\": trailing backslash`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := ToListValue(tc.input.value, tc.input.elemType, tc.input.separator)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("ToListValue() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToListValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToListValue() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToParameterValue_List(t *testing.T) {
+	got, err := ToParameterValue("work,home", LIST)
+	if err != nil {
+		t.Fatalf("ToParameterValue() error = %v", err)
+	}
+	want := []interface{}{"work", "home"}
+	if !reflect.DeepEqual(got.Value(), want) {
+		t.Errorf("ToParameterValue() = %v, want %v", got.Value(), want)
+	}
+}
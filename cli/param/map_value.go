@@ -0,0 +1,70 @@
+package param
+
+import "strings"
+
+// DuplicateKeyPolicy decides what happens when a key is given more than once to a map-valued option.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyError rejects a key that is given more than once.
+	DuplicateKeyError DuplicateKeyPolicy = iota
+	// DuplicateKeyLast keeps the value given last.
+	DuplicateKeyLast
+	// DuplicateKeyFirst keeps the value given first.
+	DuplicateKeyFirst
+)
+
+const (
+	mapEntrySeparator = ","
+	mapKeySeparator   = "="
+)
+
+// ToMapValue parses comma-separated key=value entries, as in "jira=ABC-12,owner=kenji",
+// into a MAP value whose values are converted to valueType.
+// Keys given more than once are resolved by the policy.
+func ToMapValue(value string, valueType Type, policy DuplicateKeyPolicy) (*Value, error) {
+	entries := make(map[string]Value)
+	for _, entry := range strings.Split(value, mapEntrySeparator) {
+		key, raw, ok := strings.Cut(entry, mapKeySeparator)
+		if !ok || key == "" {
+			return nil, errorf("invalid map entry \"%s\": expected key=value", entry)
+		}
+		converted, err := ToParameterValue(raw, valueType)
+		if err != nil {
+			return nil, errorf("key %s: %w", key, err)
+		}
+		if err := putMapEntry(entries, key, *converted, policy); err != nil {
+			return nil, err
+		}
+	}
+	return NewMapParameterPtr(entries), nil
+}
+
+// MergeMapValues returns a MAP value with the entries of previous and next,
+// such as those of two occurrences of the same option. Keys in both are resolved by the policy.
+func MergeMapValues(previous Value, next Value, policy DuplicateKeyPolicy) (*Value, error) {
+	entries := make(map[string]Value, len(previous.MapVal)+len(next.MapVal))
+	for key, value := range previous.MapVal {
+		entries[key] = value
+	}
+	for key, value := range next.MapVal {
+		if err := putMapEntry(entries, key, value, policy); err != nil {
+			return nil, err
+		}
+	}
+	return NewMapParameterPtr(entries), nil
+}
+
+// putMapEntry sets the key of entries to value unless the policy keeps an existing value or rejects the key.
+func putMapEntry(entries map[string]Value, key string, value Value, policy DuplicateKeyPolicy) error {
+	if _, ok := entries[key]; ok {
+		switch policy {
+		case DuplicateKeyError:
+			return errorf("duplicate key %s", key)
+		case DuplicateKeyFirst:
+			return nil
+		}
+	}
+	entries[key] = value
+	return nil
+}
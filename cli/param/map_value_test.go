@@ -0,0 +1,113 @@
+package param
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToMapValue(t *testing.T) {
+	type inputType struct {
+		value     string
+		valueType Type
+		policy    DuplicateKeyPolicy
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		want       *Value
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-SingleEntry",
+			input:    inputType{value: "jira=ABC-12", valueType: STRING},
+			want:     NewMapParameterPtr(map[string]Value{"jira": *NewStringParameterPtr("ABC-12")}),
+		},
+		{
+			testName: "Ok-CommaSeparated",
+			input:    inputType{value: "estimate=3,spent=1", valueType: INT},
+			want: NewMapParameterPtr(map[string]Value{
+				"estimate": *NewIntegerParameterPtr(3),
+				"spent":    *NewIntegerParameterPtr(1),
+			}),
+		},
+		{
+			testName: "Ok-ValueWithEquals",
+			input:    inputType{value: "query=a=b", valueType: STRING},
+			want:     NewMapParameterPtr(map[string]Value{"query": *NewStringParameterPtr("a=b")}),
+		},
+		{
+			testName: "Ok-DuplicateKeyLast",
+			input:    inputType{value: "owner=kenji,owner=mei", valueType: STRING, policy: DuplicateKeyLast},
+			want:     NewMapParameterPtr(map[string]Value{"owner": *NewStringParameterPtr("mei")}),
+		},
+		{
+			testName: "Ok-DuplicateKeyFirst",
+			input:    inputType{value: "owner=kenji,owner=mei", valueType: STRING, policy: DuplicateKeyFirst},
+			want:     NewMapParameterPtr(map[string]Value{"owner": *NewStringParameterPtr("kenji")}),
+		},
+		{
+			testName:   "Error-DuplicateKey",
+			input:      inputType{value: "owner=kenji,owner=mei", valueType: STRING},
+			wantErrStr: "duplicate key owner",
+		},
+		{
+			testName:   "Error-MissingEquals",
+			input:      inputType{value: "jira=ABC-12,owner", valueType: STRING},
+			wantErrStr: "invalid map entry \"owner\": expected key=value",
+		},
+		{
+			testName:   "Error-EmptyKey",
+			input:      inputType{value: "=kenji", valueType: STRING},
+			wantErrStr: "invalid map entry \"=kenji\": expected key=value",
+		},
+		{
+			testName:   "Error-ValueType",
+			input:      inputType{value: "estimate=three", valueType: INT},
+			wantErrStr: "key estimate: cannot convert three to Integer",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := ToMapValue(tc.input.value, tc.input.valueType, tc.input.policy)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("ToMapValue() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToMapValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToMapValue() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValue_Value_Map(t *testing.T) {
+	value := NewMapParameterPtr(map[string]Value{
+		"jira":     *NewStringParameterPtr("ABC-12"),
+		"estimate": *NewIntegerParameterPtr(3),
+	})
+	want := map[string]interface{}{"jira": "ABC-12", "estimate": 3}
+	if got := value.Value(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Value() = %v, want %v", got, want)
+	}
+}
+
+func TestToParameterValue_Map(t *testing.T) {
+	got, err := ToParameterValue("jira=ABC-12,owner=kenji", MAP)
+	if err != nil {
+		t.Fatalf("ToParameterValue() error = %v", err)
+	}
+	want := map[string]interface{}{"jira": "ABC-12", "owner": "kenji"}
+	if !reflect.DeepEqual(got.Value(), want) {
+		t.Errorf("ToParameterValue() = %v, want %v", got.Value(), want)
+	}
+
+	if _, err := ToParameterValue("jira=ABC-12,jira=ABC-13", MAP); err == nil || err.Error() != "duplicate key jira" {
+		t.Errorf("ToParameterValue() error = %v, wantErrStr %q", err, "duplicate key jira")
+	}
+}
@@ -0,0 +1,41 @@
+package param
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	translatorMu sync.RWMutex
+	translator   = func(format string) string { return format }
+)
+
+// SetTranslator replaces the function that translates the English message formats of the package,
+// such as "cannot convert %s to Integer", into the language of the user.
+// The translation must use the same verbs in the same order as the format.
+// A nil translator restores the English messages.
+func SetTranslator(translate func(format string) string) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+	if translate == nil {
+		translate = func(format string) string { return format }
+	}
+	translator = translate
+}
+
+// translate returns the translation of the message format.
+func translate(format string) string {
+	translatorMu.RLock()
+	defer translatorMu.RUnlock()
+	return translator(format)
+}
+
+// errorf is fmt.Errorf with the format translated.
+func errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(translate(format), args...)
+}
+
+// sprintf is fmt.Sprintf with the format translated.
+func sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(translate(format), args...)
+}
@@ -5,10 +5,33 @@ import (
 	"strings"
 )
 
+// Option is a named parameter of a command that starts with `--`.
+// A Negatable flag option can also be given as `--no-<name>` to set its value to false.
+// A counting option (IsCount) is a flag whose INT value is the number of times it is given.
+// Short is an optional single-letter form such as `-v`; the short forms of flags can be combined, as in `-vv`.
+// A Hidden option is accepted but not shown in help text or completions,
+// and a warning is shown when a Deprecated option is used.
+// EnvVar names an environment variable that provides the value when the option is not given,
+// and Complete suggests values for the option on the command line.
+// A MAP option collects key=value entries whose values have the ElemType,
+// and resolves keys given more than once by its DuplicateKeys policy.
+// A LIST option collects the elements of the ElemType given in each occurrence, delimited by its Separator.
 type Option struct {
-	Name   string
-	Type   Type
-	IsFlag bool
+	Name          string
+	Type          Type
+	IsFlag        bool
+	Validators    []Validator
+	Negatable     bool
+	Description   string
+	IsCount       bool
+	Short         string
+	Hidden        bool
+	Deprecated    *Deprecation
+	EnvVar        string
+	Complete      CompleteFunc
+	ElemType      Type
+	DuplicateKeys DuplicateKeyPolicy
+	Separator     string
 }
 
 func NewOption(name string, tp Type) (*Option, error) {
@@ -35,12 +58,141 @@ func NewFlagOption(name string) (*Option, error) {
 	}, nil
 }
 
+// NewNegatableFlagOption creates a flag option that also accepts the `--no-<name>` form,
+// which sets its value to false, e.g. `--color` and `--no-color`.
+func NewNegatableFlagOption(name string) (*Option, error) {
+	option, err := NewFlagOption(name)
+	if err != nil {
+		return nil, err
+	}
+	option.Negatable = true
+	return option, nil
+}
+
+// NewCountOption creates a flag option whose INT value is incremented each time it is given,
+// e.g. for verbosity levels given as `--verbose --verbose`.
+func NewCountOption(name string) (*Option, error) {
+	err := isValidOption(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Option{
+		Name:    name,
+		Type:    INT,
+		IsFlag:  true,
+		IsCount: true,
+	}, nil
+}
+
+// NewMapOption creates an option that takes key=value entries whose values are converted to valueType,
+// e.g. `--meta jira=ABC-12 --meta owner=kenji` or `--meta jira=ABC-12,owner=kenji`.
+// The entries of all occurrences of the option are collected into one MAP value.
+func NewMapOption(name string, valueType Type) (*Option, error) {
+	option, err := NewOption(name, MAP)
+	if err != nil {
+		return nil, err
+	}
+	option.ElemType = valueType
+	return option, nil
+}
+
+// NewListOption creates an option that takes elements of elemType delimited by DefaultListSeparator,
+// e.g. `--tags work,home`. The elements of all occurrences of the option are collected into one LIST value.
+func NewListOption(name string, elemType Type) (*Option, error) {
+	option, err := NewOption(name, LIST)
+	if err != nil {
+		return nil, err
+	}
+	option.ElemType = elemType
+	option.Separator = DefaultListSeparator
+	return option, nil
+}
+
+// ParseValue converts the value given for the option on the command line to the type of the option.
+func (o *Option) ParseValue(value string) (*Value, error) {
+	switch o.Type {
+	case MAP:
+		return ToMapValue(value, o.ElemType, o.DuplicateKeys)
+	case LIST:
+		return ToListValue(value, o.ElemType, o.Separator)
+	default:
+		return ToParameterValue(value, o.Type)
+	}
+}
+
+// Merge combines the values of two occurrences of the option.
+// The entries of a MAP option are merged according to its DuplicateKeys policy,
+// the elements of a LIST option are appended, and for any other option the next value replaces the previous one.
+func (o *Option) Merge(previous Value, next Value) (*Value, error) {
+	switch o.Type {
+	case MAP:
+		return MergeMapValues(previous, next, o.DuplicateKeys)
+	case LIST:
+		return NewListParameterPtr(append(append([]Value{}, previous.ListVal...), next.ListVal...)), nil
+	default:
+		return &next, nil
+	}
+}
+
+// SetShort sets the single-letter short form of the option, such as `-v`.
+func (o *Option) SetShort(short string) error {
+	if len(short) != 2 || short[0] != '-' || !isShortLetter(short[1]) {
+		return errorf("short name must be '-' followed by a letter or digit")
+	}
+	o.Short = short
+	return nil
+}
+
+// Deprecate marks the option as deprecated, with the option to use instead and a message, which may be empty.
+func (o *Option) Deprecate(replacement string, message string) {
+	o.Deprecated = &Deprecation{Replacement: replacement, Message: message}
+}
+
+func isShortLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
 func isValidOption(name string) error {
 	if len(name) == 0 {
-		return fmt.Errorf("name must not be empty")
+		return errorf("name must not be empty")
 	}
 	if !strings.HasPrefix(name, "--") {
-		return fmt.Errorf("name must start with '--'")
+		return errorf("name must start with '--'")
 	}
 	return nil
 }
+
+// AddValidators appends validators that are run against the value of the option after type conversion.
+func (o *Option) AddValidators(validators ...Validator) {
+	o.Validators = append(o.Validators, validators...)
+}
+
+// Validate runs the validators of the option against the given value in the order they were added.
+// It returns an error naming the option and the failing rule.
+func (o *Option) Validate(value Value) error {
+	return validate("option", o.Name, o.Validators, value)
+}
+
+// NegatedName returns the `--no-<name>` form of the option name.
+func (o *Option) NegatedName() string {
+	return "--no-" + strings.TrimPrefix(o.Name, "--")
+}
+
+// DisplayName returns the option name as shown in help text:
+// `--[no-]<name>` for a negatable flag, `--<name> <type>` for an option that takes a value,
+// and the name itself for any other flag. The short form, if any, is shown first, as in `-v, --verbose`.
+func (o *Option) DisplayName() string {
+	var name string
+	switch {
+	case o.IsFlag && o.Negatable:
+		name = "--[no-]" + strings.TrimPrefix(o.Name, "--")
+	case o.IsFlag:
+		name = o.Name
+	default:
+		name = fmt.Sprintf("%s <%s>", o.Name, ParameterTypeToString(o.Type))
+	}
+	if o.Short != "" {
+		name = o.Short + ", " + name
+	}
+	return name
+}
@@ -149,3 +149,105 @@ func TestNewFlagOption(t *testing.T) {
 		})
 	}
 }
+
+func TestNewNegatableFlagOption(t *testing.T) {
+	got, err := NewNegatableFlagOption("--color")
+	if err != nil {
+		t.Fatalf("NewNegatableFlagOption() error = %v", err)
+	}
+	want := &Option{Name: "--color", Type: BOOL, IsFlag: true, Negatable: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewNegatableFlagOption() = %v, want %v", got, want)
+	}
+	if got.NegatedName() != "--no-color" {
+		t.Errorf("Option.NegatedName() = %v, want %v", got.NegatedName(), "--no-color")
+	}
+
+	if _, err := NewNegatableFlagOption("color"); err == nil || err.Error() != "name must start with '--'" {
+		t.Errorf("NewNegatableFlagOption() error = %v, wantErrStr %q", err, "name must start with '--'")
+	}
+}
+
+func TestOption_DisplayName(t *testing.T) {
+	type testCase struct {
+		testName string
+		option   *Option
+		want     string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Flag",
+			option:   &Option{Name: "--done", Type: BOOL, IsFlag: true},
+			want:     "--done",
+		},
+		{
+			testName: "Ok-NegatableFlag",
+			option:   &Option{Name: "--archive", Type: BOOL, IsFlag: true, Negatable: true},
+			want:     "--[no-]archive",
+		},
+		{
+			testName: "Ok-RegularOption",
+			option:   &Option{Name: "--priority", Type: INT},
+			want:     "--priority <int>",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := tc.option.DisplayName(); got != tc.want {
+				t.Errorf("Option.DisplayName() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewCountOption(t *testing.T) {
+	got, err := NewCountOption("--verbose")
+	if err != nil {
+		t.Fatalf("NewCountOption() error = %v", err)
+	}
+	want := &Option{Name: "--verbose", Type: INT, IsFlag: true, IsCount: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewCountOption() = %v, want %v", got, want)
+	}
+	if _, err := NewCountOption(""); err == nil || err.Error() != "name must not be empty" {
+		t.Errorf("NewCountOption() error = %v, wantErrStr %q", err, "name must not be empty")
+	}
+}
+
+func TestOption_SetShort(t *testing.T) {
+	type testCase struct {
+		testName   string
+		short      string
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{testName: "Ok-Letter", short: "-v"},
+		{testName: "Ok-Digit", short: "-1"},
+		{testName: "Error-NoDash", short: "v", wantErr: true, wantErrStr: "short name must be '-' followed by a letter or digit"},
+		{testName: "Error-TooLong", short: "-vv", wantErr: true, wantErrStr: "short name must be '-' followed by a letter or digit"},
+		{testName: "Error-DoubleDash", short: "--", wantErr: true, wantErrStr: "short name must be '-' followed by a letter or digit"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			option, _ := NewCountOption("--verbose")
+			err := option.SetShort(tc.short)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Option.SetShort() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Option.SetShort() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if option.Short != tc.short {
+				t.Errorf("Option.Short = %v, want %v", option.Short, tc.short)
+			}
+		})
+	}
+
+	option, _ := NewCountOption("--verbose")
+	_ = option.SetShort("-v")
+	if got, want := option.DisplayName(), "-v, --verbose"; got != want {
+		t.Errorf("Option.DisplayName() = %v, want %v", got, want)
+	}
+}
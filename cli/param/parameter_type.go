@@ -6,6 +6,10 @@ const (
 	STRING Type = iota
 	INT
 	BOOL
+	// MAP is a set of key=value entries whose values have the element type of the option.
+	MAP
+	// LIST is a sequence of delimited values that have the element type of the option.
+	LIST
 )
 
 func ParameterTypeToString(paramType Type) string {
@@ -16,6 +20,10 @@ func ParameterTypeToString(paramType Type) string {
 		return "int"
 	case STRING:
 		return "string"
+	case MAP:
+		return "map"
+	case LIST:
+		return "list"
 	default:
 		return "unknownType"
 	}
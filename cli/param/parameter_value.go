@@ -1,14 +1,16 @@
 package param
 
-import (
-	"fmt"
-	"strconv"
-)
+import "strconv"
 
+// Value is the converted value of an argument or option.
+// Since MapVal and ListVal hold the elements of MAP and LIST values, a Value cannot be compared with ==;
+// use reflect.DeepEqual instead.
 type Value struct {
 	StringVal string
 	IntVal    int
 	BoolVal   bool
+	MapVal    map[string]Value
+	ListVal   []Value
 	Type      Type
 }
 
@@ -20,11 +22,27 @@ func (v *Value) Value() interface{} {
 		return v.IntVal
 	case BOOL:
 		return v.BoolVal
+	case MAP:
+		values := make(map[string]interface{}, len(v.MapVal))
+		for key, value := range v.MapVal {
+			values[key] = value.Value()
+		}
+		return values
+	case LIST:
+		values := make([]interface{}, 0, len(v.ListVal))
+		for _, value := range v.ListVal {
+			values = append(values, value.Value())
+		}
+		return values
 	default:
 		return nil
 	}
 }
 
+// ToParameterValue converts a value given on the command line to the parameter type.
+// A LIST value has STRING elements separated by DefaultListSeparator;
+// ToListValue converts lists with other element types and separators.
+// A MAP value has STRING values and must not repeat a key; ToMapValue converts maps with other value types.
 func ToParameterValue(value string, paramType Type) (*Value, error) {
 	switch paramType {
 	case STRING:
@@ -32,7 +50,7 @@ func ToParameterValue(value string, paramType Type) (*Value, error) {
 	case INT:
 		intValue, err := strconv.Atoi(value)
 		if err != nil {
-			return nil, fmt.Errorf("cannot convert %s to Integer", value)
+			return nil, errorf("cannot convert %s to Integer", value)
 		}
 		return NewIntegerParameterPtr(intValue), nil
 	case BOOL:
@@ -41,11 +59,15 @@ func ToParameterValue(value string, paramType Type) (*Value, error) {
 		}
 		boolValue, err := strconv.ParseBool(value)
 		if err != nil {
-			return nil, fmt.Errorf("cannot convert %s to Boolean", value)
+			return nil, errorf("cannot convert %s to Boolean", value)
 		}
 		return NewBoolParameterPtr(boolValue), nil
+	case LIST:
+		return ToListValue(value, STRING, DefaultListSeparator)
+	case MAP:
+		return ToMapValue(value, STRING, DuplicateKeyError)
 	default:
-		return nil, fmt.Errorf("unknown parameter type %v", paramType)
+		return nil, errorf("unknown parameter type %v", paramType)
 	}
 }
 
@@ -75,3 +97,17 @@ func NewBoolParameterPtr(value bool) *Value {
 		Type:      BOOL,
 	}
 }
+
+func NewMapParameterPtr(value map[string]Value) *Value {
+	return &Value{
+		MapVal: value,
+		Type:   MAP,
+	}
+}
+
+func NewListParameterPtr(value []Value) *Value {
+	return &Value{
+		ListVal: value,
+		Type:    LIST,
+	}
+}
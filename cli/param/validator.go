@@ -0,0 +1,139 @@
+package param
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Validator checks a parameter value after it has been converted to its Type.
+// Rule is a short description of the check, such as "min(1)", that is included
+// in the error message together with the name of the parameter.
+type Validator struct {
+	Rule  string
+	Check func(value Value) error
+}
+
+// Min returns a Validator that requires an INT value to be greater than or equal to min.
+func Min(min int) Validator {
+	return Validator{
+		Rule: fmt.Sprintf("min(%d)", min),
+		Check: func(value Value) error {
+			if value.Type != INT {
+				return errorf("expected int value, got %s", ParameterTypeToString(value.Type))
+			}
+			if value.IntVal < min {
+				return errorf("%d is less than %d", value.IntVal, min)
+			}
+			return nil
+		},
+	}
+}
+
+// Max returns a Validator that requires an INT value to be less than or equal to max.
+func Max(max int) Validator {
+	return Validator{
+		Rule: fmt.Sprintf("max(%d)", max),
+		Check: func(value Value) error {
+			if value.Type != INT {
+				return errorf("expected int value, got %s", ParameterTypeToString(value.Type))
+			}
+			if value.IntVal > max {
+				return errorf("%d is greater than %d", value.IntVal, max)
+			}
+			return nil
+		},
+	}
+}
+
+// Length returns a Validator that requires the number of characters of a STRING value
+// to be between min and max inclusive. A negative max means there is no upper limit.
+func Length(min int, max int) Validator {
+	rule := fmt.Sprintf("length(%d,%d)", min, max)
+	if max < 0 {
+		rule = fmt.Sprintf("length(%d,)", min)
+	}
+	return Validator{
+		Rule: rule,
+		Check: func(value Value) error {
+			if value.Type != STRING {
+				return errorf("expected string value, got %s", ParameterTypeToString(value.Type))
+			}
+			length := utf8.RuneCountInString(value.StringVal)
+			if length < min {
+				return errorf("length %d is less than %d", length, min)
+			}
+			if max >= 0 && length > max {
+				return errorf("length %d is greater than %d", length, max)
+			}
+			return nil
+		},
+	}
+}
+
+// NotEmpty returns a Validator that requires a STRING value to contain at least one character.
+func NotEmpty() Validator {
+	return Validator{
+		Rule: "not-empty",
+		Check: func(value Value) error {
+			if value.Type != STRING {
+				return errorf("expected string value, got %s", ParameterTypeToString(value.Type))
+			}
+			if value.StringVal == "" {
+				return errorf("must not be empty")
+			}
+			return nil
+		},
+	}
+}
+
+// Pattern returns a Validator that requires a STRING value to match the regular expression.
+func Pattern(re *regexp.Regexp) Validator {
+	return Validator{
+		Rule: fmt.Sprintf("pattern(%s)", re.String()),
+		Check: func(value Value) error {
+			if value.Type != STRING {
+				return errorf("expected string value, got %s", ParameterTypeToString(value.Type))
+			}
+			if !re.MatchString(value.StringVal) {
+				return errorf("%s does not match %s", value.StringVal, re.String())
+			}
+			return nil
+		},
+	}
+}
+
+// OneOf returns a Validator that requires the value, formatted as a string, to be one of the choices.
+func OneOf(choices ...string) Validator {
+	return Validator{
+		Rule: fmt.Sprintf("one-of%v", choices),
+		Check: func(value Value) error {
+			str := fmt.Sprint(value.Value())
+			for _, choice := range choices {
+				if str == choice {
+					return nil
+				}
+			}
+			return errorf("%s is not one of %v", str, choices)
+		},
+	}
+}
+
+// Custom returns a Validator with the given rule name and check function.
+func Custom(rule string, check func(value Value) error) Validator {
+	return Validator{
+		Rule:  rule,
+		Check: check,
+	}
+}
+
+// validate runs the validators against the value in order.
+// It returns an error naming the kind and name of the parameter and the rule of the first failing validator.
+func validate(kind string, name string, validators []Validator, value Value) error {
+	for _, validator := range validators {
+		if err := validator.Check(value); err != nil {
+			return errorf("invalid %s \"%s\": %s: %w", translate(kind), name, validator.Rule, err)
+		}
+	}
+	return nil
+}
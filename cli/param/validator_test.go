@@ -0,0 +1,166 @@
+package param
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestValidator_Check(t *testing.T) {
+	type inputType struct {
+		validator Validator
+		value     *Value
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		wantRule   string
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Min",
+			input:    inputType{validator: Min(1), value: NewIntegerParameterPtr(1)},
+			wantRule: "min(1)",
+		},
+		{
+			testName:   "Error-Min",
+			input:      inputType{validator: Min(1), value: NewIntegerParameterPtr(0)},
+			wantRule:   "min(1)",
+			wantErr:    true,
+			wantErrStr: "0 is less than 1",
+		},
+		{
+			testName:   "Error-MinWrongType",
+			input:      inputType{validator: Min(1), value: NewStringParameterPtr("1")},
+			wantRule:   "min(1)",
+			wantErr:    true,
+			wantErrStr: "expected int value, got string",
+		},
+		{
+			testName: "Ok-Max",
+			input:    inputType{validator: Max(5), value: NewIntegerParameterPtr(5)},
+			wantRule: "max(5)",
+		},
+		{
+			testName:   "Error-Max",
+			input:      inputType{validator: Max(5), value: NewIntegerParameterPtr(6)},
+			wantRule:   "max(5)",
+			wantErr:    true,
+			wantErrStr: "6 is greater than 5",
+		},
+		{
+			testName: "Ok-Length",
+			input:    inputType{validator: Length(1, 3), value: NewStringParameterPtr("うさぎ")},
+			wantRule: "length(1,3)",
+		},
+		{
+			testName:   "Error-LengthTooShort",
+			input:      inputType{validator: Length(2, -1), value: NewStringParameterPtr("a")},
+			wantRule:   "length(2,)",
+			wantErr:    true,
+			wantErrStr: "length 1 is less than 2",
+		},
+		{
+			testName:   "Error-LengthTooLong",
+			input:      inputType{validator: Length(1, 3), value: NewStringParameterPtr("rabbit")},
+			wantRule:   "length(1,3)",
+			wantErr:    true,
+			wantErrStr: "length 6 is greater than 3",
+		},
+		{
+			testName: "Ok-NotEmpty",
+			input:    inputType{validator: NotEmpty(), value: NewStringParameterPtr("Buy milk")},
+			wantRule: "not-empty",
+		},
+		{
+			testName:   "Error-NotEmpty",
+			input:      inputType{validator: NotEmpty(), value: NewStringParameterPtr("")},
+			wantRule:   "not-empty",
+			wantErr:    true,
+			wantErrStr: "must not be empty",
+		},
+		{
+			testName: "Ok-Pattern",
+			input:    inputType{validator: Pattern(regexp.MustCompile(`^[a-z-]+$`)), value: NewStringParameterPtr("work-home")},
+			wantRule: "pattern(^[a-z-]+$)",
+		},
+		{
+			testName:   "Error-Pattern",
+			input:      inputType{validator: Pattern(regexp.MustCompile(`^[a-z-]+$`)), value: NewStringParameterPtr("Work!")},
+			wantRule:   "pattern(^[a-z-]+$)",
+			wantErr:    true,
+			wantErrStr: "Work! does not match ^[a-z-]+$",
+		},
+		{
+			testName: "Ok-OneOfInteger",
+			input:    inputType{validator: OneOf("1", "2"), value: NewIntegerParameterPtr(2)},
+			wantRule: "one-of[1 2]",
+		},
+		{
+			testName:   "Error-OneOf",
+			input:      inputType{validator: OneOf("asc", "desc"), value: NewStringParameterPtr("up")},
+			wantRule:   "one-of[asc desc]",
+			wantErr:    true,
+			wantErrStr: "up is not one of [asc desc]",
+		},
+		{
+			testName: "Error-Custom",
+			input: inputType{
+				validator: Custom("even", func(value Value) error {
+					if value.IntVal%2 != 0 {
+						return fmt.Errorf("%d is odd", value.IntVal)
+					}
+					return nil
+				}),
+				value: NewIntegerParameterPtr(3),
+			},
+			wantRule:   "even",
+			wantErr:    true,
+			wantErrStr: "3 is odd",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if tc.input.validator.Rule != tc.wantRule {
+				t.Errorf("Validator.Rule = %q, want %q", tc.input.validator.Rule, tc.wantRule)
+			}
+			err := tc.input.validator.Check(*tc.input.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validator.Check() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr && err.Error() != tc.wantErrStr {
+				t.Errorf("Validator.Check() error = %q, wantErrStr %q", err, tc.wantErrStr)
+			}
+		})
+	}
+}
+
+func TestArgument_Validate(t *testing.T) {
+	arg, _ := NewArgument("priority", INT)
+	arg.AddValidators(Min(1), Max(5))
+
+	if err := arg.Validate(*NewIntegerParameterPtr(3)); err != nil {
+		t.Errorf("Argument.Validate() error = %v, want nil", err)
+	}
+	err := arg.Validate(*NewIntegerParameterPtr(7))
+	wantErrStr := "invalid argument \"priority\": max(5): 7 is greater than 5"
+	if err == nil || err.Error() != wantErrStr {
+		t.Errorf("Argument.Validate() error = %v, wantErrStr %q", err, wantErrStr)
+	}
+}
+
+func TestOption_Validate(t *testing.T) {
+	opt, _ := NewOption("--tag", STRING)
+	opt.AddValidators(NotEmpty(), Pattern(regexp.MustCompile(`^[a-z]+$`)))
+
+	if err := opt.Validate(*NewStringParameterPtr("work")); err != nil {
+		t.Errorf("Option.Validate() error = %v, want nil", err)
+	}
+	err := opt.Validate(*NewStringParameterPtr(""))
+	wantErrStr := "invalid option \"--tag\": not-empty: must not be empty"
+	if err == nil || err.Error() != wantErrStr {
+		t.Errorf("Option.Validate() error = %v, wantErrStr %q", err, wantErrStr)
+	}
+}
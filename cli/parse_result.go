@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"os"
+	"rabbit-todo/cli/param"
+	"strings"
+)
+
+// ValueSource tells where the value of an argument or option came from.
+type ValueSource int
+
+const (
+	// SourceDefault is the default value of a flag or counting option that is not given.
+	SourceDefault ValueSource = iota
+	// SourceEnv is the value of the environment variable of an option.
+	SourceEnv
+	// SourceConfig is the value of an option in the config of the parser.
+	SourceConfig
+	// SourceFlag is a value given on the command line.
+	SourceFlag
+	// SourcePrompt is the value of a missing argument that the user was asked for.
+	SourcePrompt
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourceFlag:
+		return "flag"
+	case SourcePrompt:
+		return "prompt"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseResult is the interpretation of a command line by Parser.Parse.
+// Command is the command that would be executed, or nil if there is none, in which case Plugin
+// names the plugin that would be run instead, if any.
+// Args and Opts hold the values that would be passed to the action, with the global options merged into Opts,
+// and Sources holds where each value came from, by argument name or option name without the `--` prefix.
+// Err is the error that executing the command line would fail with before running the action.
+type ParseResult struct {
+	Command *Command
+	Plugin  string
+	Args    map[string]param.Value
+	Opts    map[string]param.Value
+	Sources map[string]ValueSource
+	Err     error
+
+	globalSources map[string]ValueSource
+	pluginPath    string
+	pluginParams  []string
+	rawParams     []string
+	restoreLocale func()
+}
+
+// SetConfig sets the values of options that are not given on the command line or by their environment variable,
+// such as those read from a configuration file. The keys are option names without the `--` prefix
+// and apply to the global options and the options of every command with that name.
+func (p *Parser) SetConfig(values map[string]string) {
+	p.config = values
+}
+
+// Parse interprets the command line like Execute, but returns the result instead of running the action.
+// It neither asks for missing arguments nor writes warnings about deprecated commands and options.
+// The locale selected by the --lang option only applies to the messages of the result.
+func (p *Parser) Parse(args []string) ParseResult {
+	result := p.parse(args, p.IO(), false)
+	result.restoreLocale()
+	return result
+}
+
+// parse interprets the command line. If prompting is true and prompting is enabled for the parser,
+// missing arguments are asked for on stdio unless the --no-input flag is given.
+// A command that takes its parameters unparsed, such as "__complete", receives all the arguments after its name.
+// The locale selected by the --lang option is in effect until the restoreLocale function of the result is called.
+func (p *Parser) parse(args []string, stdio *IO, prompting bool) ParseResult {
+	noRestore := func() {}
+	if len(args) > 0 {
+		if command, _ := p.findCommand(args[0], nil); command != nil && command.rawAction != nil {
+			return ParseResult{Command: command, rawParams: args[1:], restoreLocale: noRestore}
+		}
+	}
+	if p.responseFiles {
+		expanded, err := expandResponseFiles(args)
+		if err != nil {
+			return ParseResult{Err: err, restoreLocale: noRestore}
+		}
+		args = expanded
+	}
+
+	args, err := p.expandAliases(args)
+	if err != nil {
+		return ParseResult{Err: err, restoreLocale: noRestore}
+	}
+
+	commandName, params, globalOpts, globalSources, err := p.parseGlobalOptions(args)
+	if err != nil {
+		return ParseResult{Err: err, restoreLocale: noRestore}
+	}
+	result := ParseResult{Opts: globalOpts, Sources: globalSources, globalSources: globalSources, restoreLocale: noRestore}
+	if p.localeOption && globalSources[strings.TrimPrefix(langOptionName, optionPrefix)] == SourceFlag {
+		result.restoreLocale = overrideLocale(globalOpts[strings.TrimPrefix(langOptionName, optionPrefix)].StringVal)
+	}
+	if commandName == "" {
+		result.Err = Errorf("no command provided")
+		return result
+	}
+
+	command, params := p.findCommand(commandName, params)
+	if command == nil {
+		if path := p.findPlugin(commandName); path != "" {
+			result.Plugin = commandName
+			result.pluginPath = path
+			result.pluginParams = params
+			return result
+		}
+		result.Err = Errorf("unknown command %s", commandName)
+		return result
+	}
+	result.Command = command
+
+	exec := execution{stdio: stdio, config: p.config}
+	if prompting && p.prompting && !globalOpts[strings.TrimPrefix(noInputOptionName, optionPrefix)].BoolVal {
+		exec.prompt = newPrompt(exec.stdio)
+	}
+	commandArgs, commandOpts, sources, err := command.validate(params, exec)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	for name, value := range globalOpts {
+		commandOpts[name] = value
+	}
+	for name, source := range globalSources {
+		sources[name] = source
+	}
+	result.Args = commandArgs
+	result.Opts = commandOpts
+	result.Sources = sources
+	return result
+}
+
+// resolveOptions sets the options that are not in sources from their environment variable,
+// or else from the config, and records the source of every option that has a value.
+// It returns an error if such a value cannot be converted to the type of the option.
+func resolveOptions(options []*param.Option, opts map[string]param.Value, sources map[string]ValueSource, config map[string]string) error {
+	for _, option := range options {
+		name := strings.TrimPrefix(option.Name, optionPrefix)
+		if _, ok := sources[name]; ok {
+			continue
+		}
+		if value, ok := os.LookupEnv(option.EnvVar); ok && option.EnvVar != "" {
+			converted, err := option.ParseValue(value)
+			if err != nil {
+				return Errorf("invalid value of environment variable %s: %w", option.EnvVar, err)
+			}
+			opts[name] = *converted
+			sources[name] = SourceEnv
+			continue
+		}
+		if value, ok := config[name]; ok {
+			converted, err := option.ParseValue(value)
+			if err != nil {
+				return Errorf("invalid config value of option %s: %w", option.Name, err)
+			}
+			opts[name] = *converted
+			sources[name] = SourceConfig
+			continue
+		}
+		if _, ok := opts[name]; ok {
+			sources[name] = SourceDefault
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_Parse(t *testing.T) {
+	executed := false
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		executed = true
+		return "", nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	projectOption, _ := param.NewOption("--project", param.STRING)
+	projectOption.EnvVar = "TODO_PROJECT"
+	priorityOption, _ := param.NewOption("--priority", param.INT)
+	urgentOption, _ := param.NewFlagOption("--urgent")
+	verboseOption, _ := param.NewCountOption("--verbose")
+	colorOption, _ := param.NewNegatableFlagOption("--color")
+	colorOption.EnvVar = "TODO_COLOR"
+
+	add := NewCommand("add", testAction)
+	_ = add.AddArgument(titleArg)
+	_ = add.AddOption(projectOption)
+	_ = add.AddOption(priorityOption)
+	_ = add.AddOption(urgentOption)
+	rm := NewCommand("rm", testAction)
+	rm.Deprecate("remove", "")
+
+	var stderr bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &stderr})
+	_ = parser.AddOption(verboseOption)
+	_ = parser.AddOption(colorOption)
+	_ = parser.AddCommand(add)
+	_ = parser.AddCommand(rm)
+	parser.SetConfig(map[string]string{"priority": "2", "color": "true"})
+
+	type testCase struct {
+		testName    string
+		args        []string
+		env         map[string]string
+		wantCommand string
+		wantArgs    map[string]param.Value
+		wantOpts    map[string]param.Value
+		wantSources map[string]ValueSource
+		wantErrStr  string
+	}
+	tests := []testCase{
+		{
+			testName:    "Ok-Sources",
+			args:        []string{"add", "milk", "--urgent"},
+			env:         map[string]string{"TODO_PROJECT": "home"},
+			wantCommand: "add",
+			wantArgs:    map[string]param.Value{"title": *param.NewStringParameterPtr("milk")},
+			wantOpts: map[string]param.Value{
+				"project":  *param.NewStringParameterPtr("home"),
+				"priority": *param.NewIntegerParameterPtr(2),
+				"urgent":   *param.NewBoolParameterPtr(true),
+				"verbose":  *param.NewIntegerParameterPtr(0),
+				"color":    *param.NewBoolParameterPtr(true),
+			},
+			wantSources: map[string]ValueSource{
+				"title":    SourceFlag,
+				"project":  SourceEnv,
+				"priority": SourceConfig,
+				"urgent":   SourceFlag,
+				"verbose":  SourceDefault,
+				"color":    SourceConfig,
+			},
+		},
+		{
+			testName:    "Ok-FlagOverEnvAndConfig",
+			args:        []string{"--no-color", "add", "milk", "--project", "work", "--priority", "1"},
+			env:         map[string]string{"TODO_PROJECT": "home", "TODO_COLOR": "true"},
+			wantCommand: "add",
+			wantArgs:    map[string]param.Value{"title": *param.NewStringParameterPtr("milk")},
+			wantOpts: map[string]param.Value{
+				"project":  *param.NewStringParameterPtr("work"),
+				"priority": *param.NewIntegerParameterPtr(1),
+				"urgent":   *param.NewBoolParameterPtr(false),
+				"verbose":  *param.NewIntegerParameterPtr(0),
+				"color":    *param.NewBoolParameterPtr(false),
+			},
+			wantSources: map[string]ValueSource{
+				"title":    SourceFlag,
+				"project":  SourceFlag,
+				"priority": SourceFlag,
+				"urgent":   SourceDefault,
+				"verbose":  SourceDefault,
+				"color":    SourceFlag,
+			},
+		},
+		{
+			testName:    "Ok-EnvOverConfig",
+			args:        []string{"rm"},
+			env:         map[string]string{"TODO_COLOR": "false"},
+			wantCommand: "rm",
+			wantArgs:    map[string]param.Value{},
+			wantOpts: map[string]param.Value{
+				"verbose": *param.NewIntegerParameterPtr(0),
+				"color":   *param.NewBoolParameterPtr(false),
+			},
+			wantSources: map[string]ValueSource{
+				"verbose": SourceDefault,
+				"color":   SourceEnv,
+			},
+		},
+		{
+			testName:    "Error-NotEnoughArguments",
+			args:        []string{"add"},
+			wantCommand: "add",
+			wantErrStr:  "not enough arguments: actual 0, expected 1",
+		},
+		{
+			testName:   "Error-InvalidEnv",
+			args:       []string{"rm"},
+			env:        map[string]string{"TODO_COLOR": "maybe"},
+			wantErrStr: "invalid value of environment variable TODO_COLOR: cannot convert maybe to Boolean",
+		},
+		{
+			testName:   "Error-UnknownCommand",
+			args:       []string{"remove"},
+			wantErrStr: "unknown command remove",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			for _, name := range []string{"TODO_PROJECT", "TODO_COLOR"} {
+				t.Setenv(name, "")
+				if value, ok := tt.env[name]; ok {
+					t.Setenv(name, value)
+				} else {
+					_ = os.Unsetenv(name)
+				}
+			}
+
+			got := parser.Parse(tt.args)
+			if executed {
+				t.Fatalf("Parser.Parse() executed the action")
+			}
+			if stderr.Len() > 0 {
+				t.Errorf("Parser.Parse() stderr = %q, want nothing", stderr.String())
+			}
+			gotCommand := ""
+			if got.Command != nil {
+				gotCommand = got.Command.Name
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("ParseResult.Command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+			if tt.wantErrStr != "" {
+				if got.Err == nil || got.Err.Error() != tt.wantErrStr {
+					t.Errorf("ParseResult.Err = %v, wantErrStr %q", got.Err, tt.wantErrStr)
+				}
+				return
+			}
+			if got.Err != nil {
+				t.Fatalf("ParseResult.Err = %v", got.Err)
+			}
+			if !reflect.DeepEqual(got.Args, tt.wantArgs) {
+				t.Errorf("ParseResult.Args = %v, want %v", got.Args, tt.wantArgs)
+			}
+			if !reflect.DeepEqual(got.Opts, tt.wantOpts) {
+				t.Errorf("ParseResult.Opts = %v, want %v", got.Opts, tt.wantOpts)
+			}
+			if !reflect.DeepEqual(got.Sources, tt.wantSources) {
+				t.Errorf("ParseResult.Sources = %v, want %v", got.Sources, tt.wantSources)
+			}
+		})
+	}
+}
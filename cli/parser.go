@@ -1,50 +1,336 @@
 package cli
 
-import "fmt"
+import (
+	"io"
+	"rabbit-todo/cli/param"
+	"rabbit-todo/cli/style"
+	"strings"
+)
 
-// Parser holds a list of available commands
+// Parser holds a list of available commands, the global options shared by all of them,
+// and the IO they are executed with.
 type Parser struct {
-	commands []Command
+	commands      []Command
+	options       []*param.Option
+	aliases       []Alias
+	stdio         *IO
+	middlewares   []Middleware
+	prompting     bool
+	responseFiles bool
+	plugins       *PluginConfig
+	categories    []string
+	localeOption  bool
+	config        map[string]string
+	colorOption   bool
+	paging        bool
 }
 
-func NewParser() Parser {
-	return Parser{make([]Command, 0)}
+// NewParser constructs a Parser without commands or global options that executes them with the standard streams.
+// The parser is returned by pointer because commands such as the batch and shell commands refer back to it.
+func NewParser() *Parser {
+	return &Parser{
+		commands: make([]Command, 0),
+		options:  make([]*param.Option, 0),
+		stdio:    NewIO(),
+	}
+}
+
+// SetIO replaces the IO that streaming commands are executed with.
+// It is mainly used by tests to capture output instead of writing to the standard streams.
+func (p *Parser) SetIO(stdio *IO) {
+	p.stdio = stdio
+}
+
+// IO returns the IO that streaming commands are executed with.
+// It falls back to the standard streams when the parser was not constructed by NewParser.
+func (p *Parser) IO() *IO {
+	if p.stdio == nil {
+		return NewIO()
+	}
+	return p.stdio
+}
+
+// Use appends middlewares that wrap the action of every command executed by the parser.
+// They run outside any per-command middlewares, in the order they are added.
+func (p *Parser) Use(middlewares ...Middleware) {
+	p.middlewares = append(p.middlewares, middlewares...)
 }
 
 // Execute finds and executes a command based on the provided arguments.
-// The first argument should be the command name followed by its parameters.
+// The first argument that is not a global option should be the command name followed by its parameters.
+// Global options may appear before or after the command name and are passed to the action with its options.
+// When response files are enabled, @path arguments are first replaced with the tokens of the file,
+// then an alias in place of the command name is replaced with its expansion.
 // It returns the result of the command execution or an error if something goes wrong.
+// The parser-wide and per-command middlewares wrap the action of the command.
+// Commands with a StreamAction write their result to the parser's IO and return an empty string.
+// When paging is enabled and the output is a terminal, the output of the command, including the result of an Action
+// followed by a newline, is written through the pager and an empty string is returned.
+// When plugins are enabled, an unknown command is run as a plugin with the remaining parameters
+// and the values of the global options in its environment.
 func (p *Parser) Execute(args []string) (string, error) {
-	if len(args) == 0 {
-		return "", fmt.Errorf("no command provided")
+	return p.execute(args, p.IO())
+}
+
+// execute finds and executes a command like Execute, but with the given IO in place of the IO of the parser,
+// so that commands such as batch can run command lines with their own IO.
+func (p *Parser) execute(args []string, stdio *IO) (string, error) {
+	result := p.parse(args, stdio, true)
+	defer result.restoreLocale()
+	if result.Err != nil {
+		return "", result.Err
+	}
+	if err := warnDeprecatedOptions(stdio, p.options, result.globalSources); err != nil {
+		return "", err
+	}
+	if result.Command == nil {
+		return "", p.runPlugin(result.Plugin, result.pluginPath, result.pluginParams, result.Opts, stdio)
+	}
+	if result.Command.rawAction != nil {
+		return "", result.Command.rawAction(stdio, result.rawParams)
 	}
 
-	commandName := args[0]
-	params := args[1:]
+	exec := execution{
+		stdio:       stdio,
+		middlewares: p.middlewares,
+	}
+	if value, ok := result.Opts[strings.TrimPrefix(colorOptionName, optionPrefix)]; ok && p.colorOption {
+		mode, err := style.ParseMode(value.StringVal)
+		if err != nil {
+			return "", err
+		}
+		stdio := *exec.stdio
+		stdio.Color = mode
+		exec.stdio = &stdio
+	}
+	pager := p.pagerFor(exec.stdio, result.Opts)
+	nested := *exec.stdio
+	nested.nested = true
+	exec.stdio = &nested
+	if pager == nil || result.Command.noPager {
+		return result.Command.run(exec, result.Args, result.Opts, result.Sources)
+	}
 
-	for _, command := range p.commands {
-		if commandName == command.Name {
-			// Execute Command
-			output, err := command.Execute(params)
-			if err != nil {
-				return "", err
+	exec.stdio = pagedIO(exec.stdio, pager)
+	output, err := result.Command.run(exec, result.Args, result.Opts, result.Sources)
+	if err == nil && output != "" {
+		_, err = io.WriteString(pager, output+"\n")
+	}
+	if closeErr := pager.Close(); err == nil {
+		err = closeErr
+	}
+	return "", err
+}
+
+// findCommand returns the command whose name matches commandName followed by the leading params,
+// so that a command named "alias add" is found for the command name "alias" and the params "add ...".
+// The command with the longest matching name wins. It returns the command and the remaining params,
+// or nil if no command matches.
+func (p *Parser) findCommand(commandName string, params []string) (*Command, []string) {
+	var found *Command
+	foundWords := 0
+	for i := range p.commands {
+		words := strings.Fields(p.commands[i].Name)
+		if len(words) == 0 || words[0] != commandName || len(words)-1 > len(params) || len(words) <= foundWords {
+			continue
+		}
+		matched := true
+		for j, word := range words[1:] {
+			if params[j] != word {
+				matched = false
+				break
 			}
-			return output, nil
 		}
+		if matched {
+			found = &p.commands[i]
+			foundWords = len(words)
+		}
+	}
+	if found == nil {
+		return nil, params
+	}
+	return found, params[foundWords-1:]
+}
+
+// EnablePrompting makes the parser ask for missing arguments of a command
+// when its IO is interactive, instead of failing with "not enough arguments".
+// It registers the global flag --no-input that turns prompting off for a single execution.
+func (p *Parser) EnablePrompting() error {
+	noInputOption, err := param.NewFlagOption(noInputOptionName)
+	if err != nil {
+		return err
+	}
+	if err := p.AddOption(noInputOption); err != nil {
+		return err
+	}
+	p.prompting = true
+	return nil
+}
+
+// EnableLocaleOption registers the global option --lang that selects the locale of the messages,
+// such as --lang ja, in place of the locale environment variables.
+// The selected locale is only in effect until the execution returns.
+func (p *Parser) EnableLocaleOption() error {
+	langOption, err := param.NewOption(langOptionName, param.STRING)
+	if err != nil {
+		return err
+	}
+	langOption.Description = "language of the messages, such as en or ja"
+	if err := p.AddOption(langOption); err != nil {
+		return err
 	}
-	return "", fmt.Errorf("unknown command %s", commandName)
+	p.localeOption = true
+	return nil
+}
+
+// EnableResponseFiles makes the parser expand arguments of the form @path
+// into the whitespace and quote separated tokens of the file at path before parsing them.
+// An argument that starts with @@ is passed on literally with the first @ removed.
+func (p *Parser) EnableResponseFiles() {
+	p.responseFiles = true
 }
 
 // AddCommand adds a new command to the parser.
 // It checks for duplicate command names to avoid conflicts.
-// If a command with the same name already exists, it returns an error.
+// If a command with the same name already exists, an alias has the name of the command,
+// or the command defines an option with the same name as a global option, it returns an error.
 // Otherwise, it appends the new command to the parser's list of commands.
 func (p *Parser) AddCommand(command Command) error {
 	for _, c := range p.commands {
 		if c.Name == command.Name {
-			return fmt.Errorf("duplicate command name %s", command.Name)
+			return Errorf("duplicate command name %s", command.Name)
+		}
+	}
+	if words := strings.Fields(command.Name); len(words) > 0 && p.findAlias(words[0]) != nil {
+		return Errorf("alias %s shadows command %s", words[0], command.Name)
+	}
+	for _, option := range p.options {
+		if conflictsWithOption(&command, option) {
+			return Errorf("option %s of command %s conflicts with global option", option.Name, command.Name)
 		}
 	}
 	p.commands = append(p.commands, command)
 	return nil
 }
+
+// AddOption adds a global option to the parser.
+// Global options are accepted by every command, before or after the command name.
+// It returns an error if a global option or an option of a registered command has the same name.
+func (p *Parser) AddOption(opt *param.Option) error {
+	if p.findOption(opt.Name) != nil || (opt.Negatable && p.findOption(opt.NegatedName()) != nil) {
+		return Errorf("duplicate option name %s", opt.Name)
+	}
+	if opt.Short != "" && p.findOption(opt.Short) != nil {
+		return Errorf("duplicate option name %s", opt.Short)
+	}
+	for _, c := range p.commands {
+		if conflictsWithOption(&c, opt) {
+			return Errorf("option %s of command %s conflicts with global option", opt.Name, c.Name)
+		}
+	}
+	p.options = append(p.options, opt)
+	return nil
+}
+
+// conflictsWithOption reports whether the command has an option that can be confused with the global option,
+// because one of its names, its negated form or its short form is the same.
+func conflictsWithOption(command *Command, opt *param.Option) bool {
+	return command.hasOption(opt.Name) ||
+		(opt.Negatable && command.hasOption(opt.NegatedName())) ||
+		(opt.Short != "" && command.hasOption(opt.Short))
+}
+
+// parseGlobalOptions separates the global options from the input arguments.
+// It returns the command name, the remaining parameters of the command in their original order,
+// a map of the global options initialized with their default values or taken from their environment variables
+// or the config of the parser, and the source of each of their values.
+// Options before the command name must be global options.
+// The short forms of the global options are expanded where a global option is read,
+// so that an option of the command and its value are passed on to the command as they are.
+func (p *Parser) parseGlobalOptions(args []string) (string, []string, map[string]param.Value, map[string]ValueSource, error) {
+	commandName := ""
+	var command *Command
+	params := make([]string, 0, len(args))
+	opts := p.initializeOptions()
+	given := make(map[string]bool)
+
+	for i := 0; i < len(args); i++ {
+		if command != nil {
+			if n := commandOptionLength(command, args, i); n > 0 {
+				params = append(params, args[i:i+n]...)
+				i += n - 1
+				continue
+			}
+		}
+		for _, arg := range expandShortOption(p.options, args[i]) {
+			option, negated := lookupOption(p.options, arg)
+			switch {
+			case option != nil && option.IsFlag:
+				// A global flag may be followed by the command name or an argument,
+				// so the "cannot have value" rule of command flags does not apply.
+				optName := strings.TrimPrefix(option.Name, optionPrefix)
+				if option.IsCount {
+					opts[optName] = *param.NewIntegerParameterPtr(opts[optName].IntVal + 1)
+					given[optName] = true
+					continue
+				}
+				if given[optName] && opts[optName].BoolVal == negated {
+					return "", nil, nil, nil, Errorf("flag-option --%s and --no-%s cannot be used together", optName, optName)
+				}
+				opts[optName] = *param.NewBoolParameterPtr(!negated)
+				given[optName] = true
+			case option != nil:
+				optName, optValue, err := processRegularOption(arg, option, args, &i)
+				if err != nil {
+					return "", nil, nil, nil, err
+				}
+				if previous, ok := opts[optName]; ok && given[optName] {
+					optValue, err = option.Merge(previous, *optValue)
+					if err != nil {
+						return "", nil, nil, nil, Errorf("invalid option \"%s\": %w", option.Name, err)
+					}
+				}
+				opts[optName] = *optValue
+				given[optName] = true
+			case commandName != "":
+				params = append(params, arg)
+			case isArgument(arg):
+				commandName = arg
+				command, _ = p.findCommand(commandName, args[i+1:])
+			default:
+				return "", nil, nil, nil, Errorf("invalid option %s", arg)
+			}
+		}
+	}
+	sources := make(map[string]ValueSource)
+	for name := range given {
+		sources[name] = SourceFlag
+	}
+	if err := resolveOptions(p.options, opts, sources, p.config); err != nil {
+		return "", nil, nil, nil, err
+	}
+	if err := validateOptionValues(p.options, opts); err != nil {
+		return "", nil, nil, nil, err
+	}
+	return commandName, params, opts, sources, nil
+}
+
+// findOption returns the global option with the given name, or nil if there is none.
+// The `--no-<name>` form of a negatable flag finds the flag.
+func (p *Parser) findOption(name string) *param.Option {
+	option, _ := lookupOption(p.options, name)
+	return option
+}
+
+// initializeOptions creates a map with default values for all global options.
+// Like command options, flags are initialized with a default boolean value of false,
+// and counting options with zero.
+func (p *Parser) initializeOptions() map[string]param.Value {
+	options := make(map[string]param.Value)
+	for _, option := range p.options {
+		if option.IsFlag {
+			options[strings.TrimPrefix(option.Name, optionPrefix)] = *flagDefault(option)
+		}
+	}
+	return options
+}
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"rabbit-todo/cli/param"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -130,7 +134,7 @@ func TestParser_Execute(t *testing.T) {
 	}
 	for _, tc := range tests {
 		t.Run(tc.testName, func(t *testing.T) {
-			parser := Parser{tc.input.commands}
+			parser := Parser{commands: tc.input.commands}
 			got, err := parser.Execute(tc.input.args)
 			isErr := err != nil
 			if isErr != tc.wantErr {
@@ -150,6 +154,7 @@ func TestParser_Execute(t *testing.T) {
 func TestParser_AddCommand(t *testing.T) {
 	type inputType struct {
 		commands []Command
+		aliases  []Alias
 		command  Command
 	}
 	type testCase struct {
@@ -184,11 +189,21 @@ func TestParser_AddCommand(t *testing.T) {
 			wantErr:    true,
 			wantErrStr: "duplicate command name command-1",
 		},
+		{
+			testName: "Error-ShadowedByAlias",
+			input: inputType{
+				aliases: []Alias{{Name: "alias", Expansion: "command-1"}},
+				command: Command{Name: "alias add"},
+			},
+			wantErr:    true,
+			wantErrStr: "alias alias shadows command alias add",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.testName, func(t *testing.T) {
 			p := &Parser{
 				commands: tc.input.commands,
+				aliases:  tc.input.aliases,
 			}
 			err := p.AddCommand(tc.input.command)
 			if (err != nil) != tc.wantErr {
@@ -199,3 +214,432 @@ func TestParser_AddCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_Execute_With_StreamAction(t *testing.T) {
+	testStreamAction := func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		for i := 0; i < args["count"].IntVal; i++ {
+			if _, err := fmt.Fprintf(stdio.Out, "task-%d\n", i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	countArg, _ := param.NewArgument("count", param.INT)
+	command := NewStreamCommand("list", testStreamAction)
+	_ = command.AddArgument(countArg)
+
+	var stdout bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &stdout})
+	if err := parser.AddCommand(command); err != nil {
+		t.Fatalf("Parser.AddCommand() error = %v", err)
+	}
+
+	got, err := parser.Execute([]string{"list", "3"})
+	if err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("Parser.Execute() = %q, want %q", got, "")
+	}
+	want := "task-0\ntask-1\ntask-2\n"
+	if stdout.String() != want {
+		t.Errorf("Parser.Execute() stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestParser_AddOption(t *testing.T) {
+	type inputType struct {
+		commands []Command
+		options  []*param.Option
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		wantErr    bool
+		wantErrStr string
+	}
+
+	toOption, _ := param.NewOption("--to", param.STRING)
+	command := NewCommand("command-1", nil)
+	_ = command.AddOption(toOption)
+
+	tests := []testCase{
+		{
+			testName: "Ok-AddOptionSuccessfully",
+			input: inputType{
+				commands: []Command{command},
+				options: []*param.Option{
+					{Name: "--data-dir", Type: param.STRING},
+					{Name: "--no-color", Type: param.BOOL, IsFlag: true},
+				},
+			},
+		},
+		{
+			testName: "Error-DuplicateOptionName",
+			input: inputType{
+				commands: []Command{command},
+				options: []*param.Option{
+					{Name: "--data-dir", Type: param.STRING},
+					{Name: "--data-dir", Type: param.STRING},
+				},
+			},
+			wantErr:    true,
+			wantErrStr: "duplicate option name --data-dir",
+		},
+		{
+			testName: "Error-ConflictWithCommandOption",
+			input: inputType{
+				commands: []Command{command},
+				options:  []*param.Option{{Name: "--to", Type: param.STRING}},
+			},
+			wantErr:    true,
+			wantErrStr: "option --to of command command-1 conflicts with global option",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			p := &Parser{commands: tc.input.commands}
+			var Err error = nil
+			for _, option := range tc.input.options {
+				if err := p.AddOption(option); err != nil {
+					Err = err
+				}
+			}
+			if (Err != nil) != tc.wantErr {
+				t.Errorf("AddOption() error = %v, wantErr %v", Err, tc.wantErr)
+			} else if Err != nil && Err.Error() != tc.wantErrStr {
+				t.Errorf("AddOption() gotErrStr = %v, wantErrStr %v", Err, tc.wantErrStr)
+			}
+		})
+	}
+}
+
+func TestParser_Execute_With_GlobalOptions(t *testing.T) {
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("dir:%s color:%t title:%s to:%s",
+			opts["data-dir"].StringVal, !opts["no-color"].BoolVal, args["title"].StringVal, opts["to"].StringVal), nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	toOption, _ := param.NewOption("--to", param.STRING)
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddOption(toOption)
+	dataDirOption, _ := param.NewOption("--data-dir", param.STRING)
+	noColorOption, _ := param.NewFlagOption("--no-color")
+
+	parser := NewParser()
+	_ = parser.AddOption(dataDirOption)
+	_ = parser.AddOption(noColorOption)
+	_ = parser.AddCommand(command)
+
+	tests := []testCase{
+		{
+			testName: "Ok-GlobalOptionsBeforeCommand",
+			args:     []string{"--data-dir", "/tmp/x", "--no-color", "add", "Buy milk"},
+			want:     "dir:/tmp/x color:false title:Buy milk to:",
+		},
+		{
+			testName: "Ok-GlobalOptionsAfterCommand",
+			args:     []string{"add", "Buy milk", "--to", "John", "--no-color", "--data-dir", "/tmp/y"},
+			want:     "dir:/tmp/y color:false title:Buy milk to:John",
+		},
+		{
+			testName: "Ok-GlobalFlagDefault",
+			args:     []string{"add", "Buy milk"},
+			want:     "dir: color:true title:Buy milk to:",
+		},
+		{
+			testName:   "Error-OnlyGlobalOptions",
+			args:       []string{"--no-color"},
+			wantErr:    true,
+			wantErrStr: "no command provided",
+		},
+		{
+			testName:   "Error-CommandOptionBeforeCommand",
+			args:       []string{"--to", "John", "add", "Buy milk"},
+			wantErr:    true,
+			wantErrStr: "invalid option --to",
+		},
+		{
+			testName:   "Error-MissingGlobalOptionValue",
+			args:       []string{"--data-dir", "--no-color", "add", "Buy milk"},
+			wantErr:    true,
+			wantErrStr: "\"--data-dir\" option requires a \"string\" type argument",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := parser.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_Execute_With_ResponseFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.txt")
+	if err := os.WriteFile(path, []byte("\"Buy milk\" --to John"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return args["title"].StringVal + " -> " + opts["to"].StringVal, nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	toOption, _ := param.NewOption("--to", param.STRING)
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddOption(toOption)
+
+	parser := NewParser()
+	_ = parser.AddCommand(command)
+
+	literal, err := parser.Execute([]string{"add", "@" + path})
+	if err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if want := "@" + path + " -> "; literal != want {
+		t.Errorf("Parser.Execute() without response files = %q, want %q", literal, want)
+	}
+
+	parser.EnableResponseFiles()
+	got, err := parser.Execute([]string{"add", "@" + path})
+	if err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if want := "Buy milk -> John"; got != want {
+		t.Errorf("Parser.Execute() = %q, want %q", got, want)
+	}
+}
+
+func TestParser_Execute_With_NegatableGlobalOption(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("color:%t", opts["color"].BoolVal), nil
+	}
+	colorOption, _ := param.NewNegatableFlagOption("--color")
+	parser := NewParser()
+	_ = parser.AddOption(colorOption)
+	_ = parser.AddCommand(NewCommand("list", testAction))
+
+	got, err := parser.Execute([]string{"--no-color", "list"})
+	if err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if want := "color:false"; got != want {
+		t.Errorf("Parser.Execute() = %q, want %q", got, want)
+	}
+
+	_, err = parser.Execute([]string{"--color", "list", "--no-color"})
+	wantErrStr := "flag-option --color and --no-color cannot be used together"
+	if err == nil || err.Error() != wantErrStr {
+		t.Errorf("Parser.Execute() error = %v, wantErrStr %q", err, wantErrStr)
+	}
+}
+
+func TestParser_Execute_With_GlobalCountOption(t *testing.T) {
+	var verbosity int
+	parser := NewParser()
+	verboseOption, _ := param.NewCountOption("--verbose")
+	_ = verboseOption.SetShort("-v")
+	_ = parser.AddOption(verboseOption)
+	parser.Use(func(cmd *Command, next Handler) Handler {
+		return func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) (string, error) {
+			verbosity = opts["verbose"].IntVal
+			return next(stdio, args, opts)
+		}
+	})
+	_ = parser.AddCommand(NewCommand("list", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("verbose:%d", opts["verbose"].IntVal), nil
+	}))
+
+	got, err := parser.Execute([]string{"-vv", "list", "--verbose"})
+	if err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	if want := "verbose:3"; got != want {
+		t.Errorf("Parser.Execute() = %q, want %q", got, want)
+	}
+	if verbosity != 3 {
+		t.Errorf("middleware verbosity = %d, want %d", verbosity, 3)
+	}
+
+	conflicting, _ := param.NewFlagOption("--verbose-output")
+	_ = conflicting.SetShort("-v")
+	command := NewCommand("show", nil)
+	_ = command.AddOption(conflicting)
+	wantErrStr := "option --verbose of command show conflicts with global option"
+	if err := parser.AddCommand(command); err == nil || err.Error() != wantErrStr {
+		t.Errorf("Parser.AddCommand() error = %v, wantErrStr %q", err, wantErrStr)
+	}
+}
+
+func TestParser_Execute_With_GlobalShortOption(t *testing.T) {
+	parser := NewParser()
+	verboseOption, _ := param.NewCountOption("--verbose")
+	_ = verboseOption.SetShort("-v")
+	_ = parser.AddOption(verboseOption)
+	titleOption, _ := param.NewOption("--title", param.STRING)
+	_ = titleOption.SetShort("-t")
+	command := NewCommand("add", func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("title:%s verbose:%d", opts["title"].StringVal, opts["verbose"].IntVal), nil
+	})
+	_ = command.AddOption(titleOption)
+	_ = parser.AddCommand(command)
+
+	type testCase struct {
+		testName string
+		args     []string
+		want     string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-ValueOfCommandOption",
+			args:     []string{"add", "--title", "-v"},
+			want:     "title:-v verbose:0",
+		},
+		{
+			testName: "Ok-ValueOfCommandShortOption",
+			args:     []string{"-v", "add", "-t", "-v", "-v"},
+			want:     "title:-v verbose:2",
+		},
+		{
+			testName: "Ok-GlobalShortOptionAfterCommand",
+			args:     []string{"add", "-vv", "--title", "milk"},
+			want:     "title:milk verbose:2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := parser.Execute(tc.args)
+			if err != nil {
+				t.Fatalf("Parser.Execute() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParser_DeprecatedAndHidden(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "removed " + args["id"].StringVal, nil
+	}
+	idArg, _ := param.NewArgument("id", param.STRING)
+	forceOption, _ := param.NewFlagOption("--force")
+	yesOption, _ := param.NewFlagOption("--yes")
+	yesOption.Deprecate("--force", "")
+	debugOption, _ := param.NewFlagOption("--debug")
+	debugOption.Hidden = true
+	quietOption, _ := param.NewFlagOption("--quiet")
+	quietOption.Deprecate("", "output is quiet by default")
+
+	remove := NewCommand("remove", testAction)
+	_ = remove.AddArgument(idArg)
+	_ = remove.AddOption(forceOption)
+	_ = remove.AddOption(yesOption)
+	_ = remove.AddOption(debugOption)
+	rm := NewCommand("rm", testAction)
+	_ = rm.AddArgument(idArg)
+	rm.Deprecate("remove", "rm will be dropped in v2")
+	schema := NewCommand("schema", testAction)
+	_ = schema.AddArgument(idArg)
+	schema.Hide()
+
+	var stdout, stderr bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &stderr})
+	_ = parser.AddOption(quietOption)
+	_ = parser.AddCommand(remove)
+	_ = parser.AddCommand(rm)
+	_ = parser.AddCommand(schema)
+
+	type testCase struct {
+		testName   string
+		args       []string
+		want       string
+		wantStderr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-CurrentCommand",
+			args:     []string{"remove", "1", "--force", "--debug"},
+			want:     "removed 1",
+		},
+		{
+			testName:   "Ok-DeprecatedCommand",
+			args:       []string{"rm", "1"},
+			want:       "removed 1",
+			wantStderr: "warning: command rm is deprecated, use remove instead: rm will be dropped in v2\n",
+		},
+		{
+			testName:   "Ok-DeprecatedOptions",
+			args:       []string{"--quiet", "remove", "1", "--yes"},
+			want:       "removed 1",
+			wantStderr: "warning: option --quiet is deprecated: output is quiet by default\nwarning: option --yes is deprecated, use --force instead\n",
+		},
+		{
+			testName: "Ok-HiddenCommand",
+			args:     []string{"schema", "1"},
+			want:     "removed 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			stderr.Reset()
+			got, err := parser.Execute(tc.args)
+			if err != nil {
+				t.Fatalf("Parser.Execute() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("Parser.Execute() stderr = %q, want %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+
+	t.Run("Ok-Help", func(t *testing.T) {
+		want := "Commands:\n  remove\n  rm      (deprecated)"
+		if got := parser.Help(); got != want {
+			t.Errorf("Parser.Help() = %q, want %q", got, want)
+		}
+		wantCommandHelp := "Usage: rm [arguments]\n" +
+			"Arguments:\n" +
+			"  id <string>\n" +
+			"This command is deprecated, use remove instead: rm will be dropped in v2"
+		if got := rm.Help(); got != wantCommandHelp {
+			t.Errorf("Command.Help() = %q, want %q", got, wantCommandHelp)
+		}
+		wantOptions := "Options:\n  --force\n  --yes"
+		if got := remove.Help(); !strings.HasSuffix(got, wantOptions) {
+			t.Errorf("Command.Help() = %q, want suffix %q", got, wantOptions)
+		}
+	})
+
+	t.Run("Ok-Complete", func(t *testing.T) {
+		if got, want := parser.Complete(nil, ""), []string{"remove", "rm"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Parser.Complete() = %q, want %q", got, want)
+		}
+		if got, want := parser.Complete([]string{"remove", "1"}, "--"), []string{"--force", "--quiet", "--yes"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Parser.Complete() = %q, want %q", got, want)
+		}
+	})
+}
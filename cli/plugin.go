@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"rabbit-todo/cli/param"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultPluginPrefix is the prefix of the executable names of plugin commands.
+const DefaultPluginPrefix = "rabbit-todo-"
+
+// PluginConfig configures how the parser finds and runs external plugin commands.
+// A plugin for the command "name" is an executable named Prefix + "name"
+// in one of Dirs, which are searched in order, or on the PATH.
+// Env holds "KEY=value" entries, such as the default location of the data directory,
+// that are added to the environment of the plugin process.
+// The values of the global options, such as --data-dir, are added after them, so that they take precedence:
+// each is exported as the EnvVar of the option or, if it has none, as the upper-cased Prefix and option name
+// with dashes replaced by underscores, such as RABBIT_TODO_DATA_DIR.
+type PluginConfig struct {
+	Prefix string
+	Dirs   []string
+	Env    []string
+}
+
+// ExitError is returned by Parser.Execute when a plugin exits with a non-zero status,
+// so that the application can exit with the same code.
+type ExitError struct {
+	Name string
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return Sprintf("plugin %s exited with status %d", e.Name, e.Code)
+}
+
+// EnablePlugins makes the parser run an external plugin when it is asked to execute an unknown command.
+// An empty Prefix in the config is replaced with DefaultPluginPrefix.
+func (p *Parser) EnablePlugins(config PluginConfig) {
+	if config.Prefix == "" {
+		config.Prefix = DefaultPluginPrefix
+	}
+	p.plugins = &config
+}
+
+// Plugins returns the sorted names of the plugin commands found in the plugin directories and on the PATH.
+// It returns nil when plugins are not enabled.
+func (p *Parser) Plugins() []string {
+	if p.plugins == nil {
+		return nil
+	}
+	dirs := append(append([]string{}, p.plugins.Dirs...), filepath.SplitList(os.Getenv("PATH"))...)
+	found := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), p.plugins.Prefix)
+			if !ok || name == "" || p.isCommandName(name) {
+				continue
+			}
+			if isExecutable(filepath.Join(dir, entry.Name())) {
+				found[name] = true
+			}
+		}
+	}
+
+	plugins := make([]string, 0, len(found))
+	for name := range found {
+		plugins = append(plugins, name)
+	}
+	sort.Strings(plugins)
+	return plugins
+}
+
+// findPlugin returns the path of the executable of the plugin command with the given name,
+// searching the plugin directories before the PATH. It returns an empty string if there is none.
+func (p *Parser) findPlugin(name string) string {
+	if p.plugins == nil || name == "" || strings.ContainsRune(name, os.PathSeparator) {
+		return ""
+	}
+	executable := p.plugins.Prefix + name
+	for _, dir := range p.plugins.Dirs {
+		path := filepath.Join(dir, executable)
+		if isExecutable(path) {
+			return path
+		}
+	}
+	path, err := exec.LookPath(executable)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// runPlugin runs the plugin executable with the given params, streaming its input and output through stdio.
+// The values of the global options in opts are passed to the plugin in its environment.
+// It returns an ExitError if the plugin exits with a non-zero status.
+func (p *Parser) runPlugin(name string, path string, params []string, opts map[string]param.Value, stdio *IO) error {
+	cmd := exec.Command(path, params...)
+	cmd.Stdin = stdio.In
+	cmd.Stdout = stdio.Out
+	cmd.Stderr = stdio.Err
+	cmd.Env = append(os.Environ(), p.plugins.Env...)
+	cmd.Env = append(cmd.Env, p.pluginOptionEnv(opts)...)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ExitError{Name: name, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return Errorf("cannot run plugin %s: %w", name, err)
+	}
+	return nil
+}
+
+// pluginOptionEnv returns the "KEY=value" entries of the global options that have a value in opts.
+func (p *Parser) pluginOptionEnv(opts map[string]param.Value) []string {
+	env := make([]string, 0, len(p.options))
+	for _, option := range p.options {
+		name := strings.TrimPrefix(option.Name, optionPrefix)
+		value, ok := opts[name]
+		if !ok {
+			continue
+		}
+		key := option.EnvVar
+		if key == "" {
+			key = strings.ToUpper(strings.ReplaceAll(p.plugins.Prefix+name, "-", "_"))
+		}
+		env = append(env, key+"="+formatEnvValue(value))
+	}
+	return env
+}
+
+// formatEnvValue formats an option value the way it is given on the command line.
+// The elements of a list and the sorted key=value entries of a map are separated by commas.
+func formatEnvValue(value param.Value) string {
+	switch value.Type {
+	case param.INT:
+		return strconv.Itoa(value.IntVal)
+	case param.BOOL:
+		return strconv.FormatBool(value.BoolVal)
+	case param.LIST:
+		elements := make([]string, 0, len(value.ListVal))
+		for _, element := range value.ListVal {
+			elements = append(elements, formatEnvValue(element))
+		}
+		return strings.Join(elements, param.DefaultListSeparator)
+	case param.MAP:
+		entries := make([]string, 0, len(value.MapVal))
+		for key, element := range value.MapVal {
+			entries = append(entries, key+"="+formatEnvValue(element))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	default:
+		return value.StringVal
+	}
+}
+
+// isExecutable reports whether the path is a regular file with an execute permission bit set.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writePlugin(t *testing.T, dir string, name string, script string, perm os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParser_Execute_With_Plugins(t *testing.T) {
+	pluginDir := t.TempDir()
+	pathDir := t.TempDir()
+	writePlugin(t, pluginDir, "rabbit-todo-jira", `echo "jira $* $RABBIT_TODO_DATA_DIR"; read line; echo "$line"; echo warn >&2`, 0o755)
+	writePlugin(t, pluginDir, "rabbit-todo-fail", "exit 3", 0o755)
+	writePlugin(t, pluginDir, "rabbit-todo-notes.txt", "", 0o644)
+	writePlugin(t, pathDir, "rabbit-todo-sync", "echo sync", 0o755)
+	t.Setenv("PATH", pathDir)
+
+	type testCase struct {
+		testName   string
+		args       []string
+		wantStdout string
+		wantStderr string
+		wantErr    bool
+		wantErrStr string
+		wantCode   int
+	}
+	tests := []testCase{
+		{
+			testName:   "Ok-PluginInDirectory",
+			args:       []string{"jira", "export", "--project", "ABC"},
+			wantStdout: "jira export --project ABC /tmp/rabbit\npiped\n",
+			wantStderr: "warn\n",
+		},
+		{
+			testName:   "Ok-GlobalOptionInEnv",
+			args:       []string{"--data-dir", "/tmp/other", "jira", "export"},
+			wantStdout: "jira export /tmp/other\npiped\n",
+			wantStderr: "warn\n",
+		},
+		{
+			testName:   "Ok-PluginOnPath",
+			args:       []string{"sync"},
+			wantStdout: "sync\n",
+		},
+		{
+			testName:   "Error-ExitCode",
+			args:       []string{"fail"},
+			wantErr:    true,
+			wantErrStr: "plugin fail exited with status 3",
+			wantCode:   3,
+		},
+		{
+			testName:   "Error-NotExecutable",
+			args:       []string{"notes.txt"},
+			wantErr:    true,
+			wantErrStr: "unknown command notes.txt",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			parser := NewParser()
+			parser.SetIO(&IO{In: strings.NewReader("piped\n"), Out: &stdout, Err: &stderr})
+			parser.EnablePlugins(PluginConfig{
+				Dirs: []string{pluginDir},
+				Env:  []string{"RABBIT_TODO_DATA_DIR=/tmp/rabbit"},
+			})
+			dataDirOption, _ := param.NewOption("--data-dir", param.STRING)
+			_ = parser.AddOption(dataDirOption)
+
+			_, err := parser.Execute(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+				var exitErr *ExitError
+				if tc.wantCode != 0 && (!errors.As(err, &exitErr) || exitErr.Code != tc.wantCode) {
+					t.Errorf("Parser.Execute() error = %v, want exit code %d", err, tc.wantCode)
+				}
+				return
+			}
+			if stdout.String() != tc.wantStdout {
+				t.Errorf("Parser.Execute() stdout = %q, want %q", stdout.String(), tc.wantStdout)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("Parser.Execute() stderr = %q, want %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+
+	t.Run("Ok-Plugins", func(t *testing.T) {
+		parser := NewParser()
+		_ = parser.AddCommand(NewCommand("sync", nil))
+		if got := parser.Plugins(); got != nil {
+			t.Errorf("Parser.Plugins() before EnablePlugins = %v, want nil", got)
+		}
+		parser.EnablePlugins(PluginConfig{Dirs: []string{pluginDir}})
+		want := []string{"fail", "jira"}
+		if got := parser.Plugins(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Parser.Plugins() = %v, want %v", got, want)
+		}
+		wantHelp := "Commands:\n  sync\nPlugins:\n  fail\n  jira"
+		if got := parser.Help(); got != wantHelp {
+			t.Errorf("Parser.Help() = %q, want %q", got, wantHelp)
+		}
+	})
+
+	t.Run("Ok-PluginsCategory", func(t *testing.T) {
+		parser := NewParser()
+		install := NewCommand("install", nil)
+		install.Category = "Plugins"
+		_ = parser.AddCommand(install)
+		parser.EnablePlugins(PluginConfig{Dirs: []string{pluginDir}})
+		wantHelp := "Plugins:\n  install\nPlugins:\n  fail\n  jira\n  sync"
+		if got := parser.Help(); got != wantHelp {
+			t.Errorf("Parser.Help() = %q, want %q", got, wantHelp)
+		}
+	})
+}
+
+func TestFormatEnvValue(t *testing.T) {
+	type testCase struct {
+		testName string
+		value    param.Value
+		want     string
+	}
+	tests := []testCase{
+		{testName: "Ok-String", value: *param.NewStringParameterPtr("/tmp/rabbit"), want: "/tmp/rabbit"},
+		{testName: "Ok-Int", value: *param.NewIntegerParameterPtr(2), want: "2"},
+		{testName: "Ok-Bool", value: *param.NewBoolParameterPtr(true), want: "true"},
+		{
+			testName: "Ok-List",
+			value:    *param.NewListParameterPtr([]param.Value{*param.NewStringParameterPtr("work"), *param.NewStringParameterPtr("home")}),
+			want:     "work,home",
+		},
+		{
+			testName: "Ok-Map",
+			value: *param.NewMapParameterPtr(map[string]param.Value{
+				"owner": *param.NewStringParameterPtr("kenji"),
+				"jira":  *param.NewStringParameterPtr("ABC-12"),
+			}),
+			want: "jira=ABC-12,owner=kenji",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if got := formatEnvValue(tc.value); got != tc.want {
+				t.Errorf("formatEnvValue() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
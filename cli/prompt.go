@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"rabbit-todo/cli/param"
+	"strings"
+)
+
+const noInputOptionName = "--no-input"
+
+// promptFunc asks the user for the value of a missing argument.
+// It returns a nil value without an error when no more input is available.
+type promptFunc func(arg *param.Argument) (*param.Value, error)
+
+// newPrompt returns a promptFunc that writes prompts to stdio.Err and reads answers from stdio.In.
+// It returns nil when stdio is not interactive, so that missing arguments are reported as errors.
+func newPrompt(stdio *IO) promptFunc {
+	if stdio == nil || !stdio.Interactive {
+		return nil
+	}
+	reader := bufio.NewReader(stdio.In)
+	return func(arg *param.Argument) (*param.Value, error) {
+		for {
+			if _, err := fmt.Fprint(stdio.Err, promptMessage(arg)); err != nil {
+				return nil, err
+			}
+			line, err := reader.ReadString('\n')
+			if err == io.EOF && line == "" {
+				return nil, nil
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+
+			line = strings.TrimRight(line, "\r\n")
+			if line == "" && arg.Default != nil {
+				return arg.Default, nil
+			}
+			value, err := param.ToParameterValue(line, arg.Type)
+			if err == nil {
+				err = arg.Validate(*value)
+			}
+			if err == nil {
+				return value, nil
+			}
+			// Ask again until the answer can be converted and validated
+			if _, err := fmt.Fprintf(stdio.Err, "%s\n", err); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// promptMessage generates the prompt shown for an argument, including its type,
+// description, choices and default value if it has any, in the selected locale.
+func promptMessage(arg *param.Argument) string {
+	var builder strings.Builder
+	builder.WriteString(Sprintf("%s (%s)", arg.Name, param.ParameterTypeToString(arg.Type)))
+	if arg.Description != "" {
+		builder.WriteString(Sprintf(" - %s", arg.Description))
+	}
+	if len(arg.Choices) > 0 {
+		builder.WriteString(Sprintf(" [%s]", strings.Join(arg.Choices, "/")))
+	}
+	if arg.Default != nil {
+		builder.WriteString(Sprintf(" (default: %v)", arg.Default.Value()))
+	}
+	builder.WriteString(": ")
+	return builder.String()
+}
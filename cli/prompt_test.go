@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"rabbit-todo/cli/param"
+	"strings"
+	"testing"
+)
+
+func TestParser_Execute_With_Prompting(t *testing.T) {
+	type inputType struct {
+		args        []string
+		stdin       string
+		interactive bool
+	}
+	type testCase struct {
+		testName   string
+		input      inputType
+		locale     string
+		want       string
+		wantStderr string
+		wantErr    bool
+		wantErrStr string
+	}
+
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return fmt.Sprintf("%s:%d:%s", args["title"].StringVal, args["priority"].IntVal, args["list"].StringVal), nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	titleArg.Description = "Title of the task"
+	priorityArg, _ := param.NewArgument("priority", param.INT)
+	priorityArg.Default = param.NewIntegerParameterPtr(3)
+	listArg, _ := param.NewArgument("list", param.STRING)
+	listArg.Choices = []string{"work", "home"}
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+	_ = command.AddArgument(priorityArg)
+	_ = command.AddArgument(listArg)
+
+	titlePrompt := "title (string) - Title of the task: "
+	priorityPrompt := "priority (int) (default: 3): "
+	listPrompt := "list (string) [work/home]: "
+
+	tests := []testCase{
+		{
+			testName: "Ok-PromptAllMissingArguments",
+			input: inputType{
+				args:        []string{"add"},
+				stdin:       "Buy milk\n\nhome\n",
+				interactive: true,
+			},
+			want:       "Buy milk:3:home",
+			wantStderr: titlePrompt + priorityPrompt + listPrompt,
+		},
+		{
+			testName: "Ok-PromptOnlyMissingArguments",
+			input: inputType{
+				args:        []string{"add", "Buy milk", "1"},
+				stdin:       "work",
+				interactive: true,
+			},
+			want:       "Buy milk:1:work",
+			wantStderr: listPrompt,
+		},
+		{
+			testName: "Ok-RepromptOnInvalidValue",
+			input: inputType{
+				args:        []string{"add", "Buy milk"},
+				stdin:       "high\n5\noffice\nwork\n",
+				interactive: true,
+			},
+			want: "Buy milk:5:work",
+			wantStderr: priorityPrompt + "cannot convert high to Integer\n" + priorityPrompt +
+				listPrompt + "invalid argument \"list\": one-of[work home]: office is not one of [work home]\n" + listPrompt,
+		},
+		{
+			testName: "Ok-PromptInSelectedLocale",
+			input: inputType{
+				args:        []string{"add", "Buy milk"},
+				stdin:       "high\n\nhome\n",
+				interactive: true,
+			},
+			locale: "ja",
+			want:   "Buy milk:3:home",
+			wantStderr: "priority (int) (デフォルト: 3): " + "high を Integer に変換できません\n" +
+				"priority (int) (デフォルト: 3): " + listPrompt,
+		},
+		{
+			testName: "Error-EndOfInput",
+			input: inputType{
+				args:        []string{"add"},
+				stdin:       "Buy milk\n",
+				interactive: true,
+			},
+			wantStderr: titlePrompt + priorityPrompt,
+			wantErr:    true,
+			wantErrStr: "not enough arguments: actual 1, expected 3",
+		},
+		{
+			testName: "Error-NotInteractive",
+			input: inputType{
+				args:        []string{"add"},
+				stdin:       "Buy milk\n\nhome\n",
+				interactive: false,
+			},
+			wantErr:    true,
+			wantErrStr: "not enough arguments: actual 0, expected 3",
+		},
+		{
+			testName: "Error-NoInputFlag",
+			input: inputType{
+				args:        []string{"--no-input", "add"},
+				stdin:       "Buy milk\n\nhome\n",
+				interactive: true,
+			},
+			wantErr:    true,
+			wantErrStr: "not enough arguments: actual 0, expected 3",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			if tc.locale != "" {
+				SetLocale(tc.locale)
+				defer SetLocale(DefaultLocale)
+			}
+			var stdout, stderr bytes.Buffer
+			parser := NewParser()
+			parser.SetIO(&IO{
+				In:          strings.NewReader(tc.input.stdin),
+				Out:         &stdout,
+				Err:         &stderr,
+				Interactive: tc.input.interactive,
+			})
+			if err := parser.EnablePrompting(); err != nil {
+				t.Fatalf("Parser.EnablePrompting() error = %v", err)
+			}
+			_ = parser.AddCommand(command)
+
+			got, err := parser.Execute(tc.input.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parser.Execute() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Parser.Execute() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if got != tc.want {
+				t.Errorf("Parser.Execute() = %q, want %q", got, tc.want)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("Parser.Execute() stderr = %q, want %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	responseFilePrefix = "@"
+	escapedAtPrefix    = "@@"
+)
+
+// expandResponseFiles replaces every token of the form @path with the tokens of that file,
+// as split by Tokenize. Response files may refer to other response files;
+// a relative path inside a response file is resolved against the directory of that file.
+// A token starting with @@ is not expanded and is passed on with the first @ removed.
+// It returns an error if a file cannot be read or tokenized, or if response files refer to each other in a cycle.
+func expandResponseFiles(args []string) ([]string, error) {
+	return expandResponseFilesIn(args, "", nil)
+}
+
+// expandResponseFilesIn expands the response files in args, resolving relative paths against dir.
+// The chain holds the paths of the response files being expanded, outermost first.
+func expandResponseFilesIn(args []string, dir string, chain []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, escapedAtPrefix) {
+			expanded = append(expanded, strings.TrimPrefix(arg, responseFilePrefix))
+			continue
+		}
+		if !strings.HasPrefix(arg, responseFilePrefix) || arg == responseFilePrefix {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		path := strings.TrimPrefix(arg, responseFilePrefix)
+		if dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, Errorf("cannot resolve response file %s: %w", path, err)
+		}
+		for i, p := range chain {
+			if p == absPath {
+				cycle := append(append([]string{}, chain[i:]...), absPath)
+				return nil, Errorf("response file cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, Errorf("cannot read response file %s: %w", path, err)
+		}
+		tokens, err := Tokenize(string(content))
+		if err != nil {
+			return nil, Errorf("invalid response file %s: %w", path, err)
+		}
+		tokens, err = expandResponseFilesIn(tokens, filepath.Dir(absPath), append(chain, absPath))
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, tokens...)
+	}
+	return expanded, nil
+}
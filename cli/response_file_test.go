@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ids.txt":          "1 2 3\n# more ids\n4",
+		"opts.txt":         "--title 'Buy milk' @nested/ids.txt",
+		"nested/ids.txt":   "5 @@literal",
+		"cycle-a.txt":      "@cycle-b.txt",
+		"cycle-b.txt":      "@cycle-a.txt",
+		"unterminated.txt": "'oops",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := func(name string) string {
+		return "@" + filepath.Join(dir, name)
+	}
+
+	type testCase struct {
+		testName      string
+		input         []string
+		want          []string
+		wantErr       bool
+		wantErrPrefix string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-NoResponseFile",
+			input:    []string{"done", "1", "@"},
+			want:     []string{"done", "1", "@"},
+		},
+		{
+			testName: "Ok-ExpandFile",
+			input:    []string{"done", at("ids.txt"), "--force"},
+			want:     []string{"done", "1", "2", "3", "4", "--force"},
+		},
+		{
+			testName: "Ok-ExpandNestedRelativeToFile",
+			input:    []string{"edit", at("opts.txt")},
+			want:     []string{"edit", "--title", "Buy milk", "5", "@literal"},
+		},
+		{
+			testName: "Ok-EscapedAt",
+			input:    []string{"add", "@@home"},
+			want:     []string{"add", "@home"},
+		},
+		{
+			testName:      "Error-Cycle",
+			input:         []string{at("cycle-a.txt")},
+			wantErr:       true,
+			wantErrPrefix: "response file cycle: " + filepath.Join(dir, "cycle-a.txt"),
+		},
+		{
+			testName:      "Error-MissingFile",
+			input:         []string{at("missing.txt")},
+			wantErr:       true,
+			wantErrPrefix: "cannot read response file " + filepath.Join(dir, "missing.txt"),
+		},
+		{
+			testName:      "Error-InvalidContent",
+			input:         []string{at("unterminated.txt")},
+			wantErr:       true,
+			wantErrPrefix: "invalid response file " + filepath.Join(dir, "unterminated.txt") + ": unterminated single quote",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := expandResponseFiles(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expandResponseFiles() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if !strings.HasPrefix(err.Error(), tc.wantErrPrefix) {
+					t.Errorf("expandResponseFiles() error = %q, wantErrPrefix %q", err, tc.wantErrPrefix)
+				}
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expandResponseFiles() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"encoding/json"
+	"rabbit-todo/cli/param"
+	"sort"
+)
+
+const (
+	// SchemaVersion is the version of the format of the JSON document generated by Parser.Schema.
+	// It is increased when a field is removed or its meaning changes.
+	SchemaVersion     = 1
+	schemaCommandName = "__schema"
+)
+
+// Schema is the machine-readable definition of the commands, global options and aliases of a parser,
+// for use by tools such as GUI wrappers, completion engines and documentation generators.
+type Schema struct {
+	Version  int             `json:"version"`
+	Commands []CommandSchema `json:"commands"`
+	Options  []OptionSchema  `json:"options"`
+	Aliases  []AliasSchema   `json:"aliases"`
+}
+
+// CommandSchema describes a command with its arguments in order, its options and the constraints between them.
+type CommandSchema struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Category    string             `json:"category,omitempty"`
+	Hidden      bool               `json:"hidden,omitempty"`
+	Deprecated  *DeprecationSchema `json:"deprecated,omitempty"`
+	Arguments   []ArgumentSchema   `json:"arguments"`
+	Options     []OptionSchema     `json:"options"`
+	Constraints []string           `json:"constraints,omitempty"`
+}
+
+// ArgumentSchema describes a positional argument of a command.
+// Type is the name of its param.Type, as returned by param.ParameterTypeToString.
+type ArgumentSchema struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Choices     []string    `json:"choices,omitempty"`
+	Rules       []string    `json:"rules,omitempty"`
+}
+
+// OptionSchema describes an option of a command or a global option.
+// ElemType is the type of the values of a map option or the elements of a list option,
+// and Separator the delimiter of the elements of a list option.
+// Default is the value of a flag or counting option that is not given,
+// and Env the environment variable that provides the value instead.
+type OptionSchema struct {
+	Name        string             `json:"name"`
+	Short       string             `json:"short,omitempty"`
+	Type        string             `json:"type"`
+	ElemType    string             `json:"elemType,omitempty"`
+	Separator   string             `json:"separator,omitempty"`
+	Flag        bool               `json:"flag,omitempty"`
+	Count       bool               `json:"count,omitempty"`
+	Negatable   bool               `json:"negatable,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Env         string             `json:"env,omitempty"`
+	Rules       []string           `json:"rules,omitempty"`
+	Hidden      bool               `json:"hidden,omitempty"`
+	Deprecated  *DeprecationSchema `json:"deprecated,omitempty"`
+}
+
+// DeprecationSchema describes the deprecation of a command or option.
+type DeprecationSchema struct {
+	Replacement string `json:"replacement,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// AliasSchema describes an alias and the command line it expands to.
+type AliasSchema struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+}
+
+// Schema returns the definition of the commands of the parser, sorted by name,
+// together with its global options and aliases.
+// Hidden and deprecated commands and options are included and marked as such.
+func (p *Parser) Schema() Schema {
+	schema := Schema{
+		Version:  SchemaVersion,
+		Commands: make([]CommandSchema, 0, len(p.commands)),
+		Options:  optionSchemas(p.options),
+		Aliases:  make([]AliasSchema, 0, len(p.aliases)),
+	}
+	for _, command := range p.commands {
+		schema.Commands = append(schema.Commands, command.schema())
+	}
+	sort.Slice(schema.Commands, func(i, j int) bool {
+		return schema.Commands[i].Name < schema.Commands[j].Name
+	})
+	for _, alias := range p.Aliases() {
+		schema.Aliases = append(schema.Aliases, AliasSchema{Name: alias.Name, Expansion: alias.Expansion})
+	}
+	return schema
+}
+
+// NewSchemaCommand constructs the hidden "__schema" command, which writes the schema of the parser as indented JSON.
+func NewSchemaCommand(p *Parser) Command {
+	command := NewStreamCommand(schemaCommandName, func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		encoder := json.NewEncoder(stdio.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(p.Schema())
+	})
+	command.Description = "Print the definition of all commands as JSON"
+	command.Hide()
+	return command
+}
+
+// schema returns the definition of the command.
+func (c *Command) schema() CommandSchema {
+	schema := CommandSchema{
+		Name:        c.Name,
+		Description: c.Description,
+		Category:    c.Category,
+		Hidden:      c.hidden,
+		Deprecated:  deprecationSchema(c.deprecated),
+		Arguments:   make([]ArgumentSchema, 0, len(c.arguments)),
+		Options:     optionSchemas(c.options),
+	}
+	for _, argument := range c.arguments {
+		argSchema := ArgumentSchema{
+			Name:        argument.Name,
+			Type:        param.ParameterTypeToString(argument.Type),
+			Description: argument.Description,
+			Choices:     argument.Choices,
+			Rules:       validatorRules(argument.Validators),
+		}
+		if argument.Default != nil {
+			argSchema.Default = argument.Default.Value()
+		}
+		schema.Arguments = append(schema.Arguments, argSchema)
+	}
+	for _, group := range c.groups {
+		schema.Constraints = append(schema.Constraints, group.String())
+	}
+	return schema
+}
+
+// optionSchemas returns the definitions of the options in order.
+func optionSchemas(options []*param.Option) []OptionSchema {
+	schemas := make([]OptionSchema, 0, len(options))
+	for _, option := range options {
+		optSchema := OptionSchema{
+			Name:        option.Name,
+			Short:       option.Short,
+			Type:        param.ParameterTypeToString(option.Type),
+			Flag:        option.IsFlag,
+			Count:       option.IsCount,
+			Separator:   option.Separator,
+			Negatable:   option.Negatable,
+			Description: option.Description,
+			Env:         option.EnvVar,
+			Rules:       validatorRules(option.Validators),
+			Hidden:      option.Hidden,
+			Deprecated:  deprecationSchema(option.Deprecated),
+		}
+		if option.Type == param.MAP || option.Type == param.LIST {
+			optSchema.ElemType = param.ParameterTypeToString(option.ElemType)
+		}
+		if option.IsFlag {
+			optSchema.Default = flagDefault(option).Value()
+		}
+		schemas = append(schemas, optSchema)
+	}
+	return schemas
+}
+
+// validatorRules returns the rules of the validators, such as "min(1)".
+func validatorRules(validators []param.Validator) []string {
+	var rules []string
+	for _, validator := range validators {
+		rules = append(rules, validator.Rule)
+	}
+	return rules
+}
+
+// deprecationSchema returns the definition of the deprecation, or nil if there is none.
+func deprecationSchema(deprecation *param.Deprecation) *DeprecationSchema {
+	if deprecation == nil {
+		return nil
+	}
+	return &DeprecationSchema{Replacement: deprecation.Replacement, Message: deprecation.Message}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"rabbit-todo/cli/param"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_Schema(t *testing.T) {
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "", nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	titleArg.Description = "title of the task"
+	priorityArg, _ := param.NewArgument("priority", param.INT)
+	priorityArg.Default = param.NewIntegerParameterPtr(3)
+	priorityArg.AddValidators(param.Min(1))
+	projectOption, _ := param.NewOption("--project", param.STRING)
+	verboseOption, _ := param.NewCountOption("--verbose")
+	_ = verboseOption.SetShort("-v")
+	colorOption, _ := param.NewNegatableFlagOption("--color")
+	colorOption.Deprecate("--style", "")
+
+	add := NewCommand("add", testAction)
+	add.Description = "Add a task"
+	add.Category = "Tasks"
+	_ = add.AddArgument(titleArg)
+	_ = add.AddArgument(priorityArg)
+	_ = add.AddOption(projectOption)
+	rm := NewCommand("rm", testAction)
+	rm.Deprecate("remove", "")
+
+	parser := NewParser()
+	_ = parser.AddOption(verboseOption)
+	_ = parser.AddOption(colorOption)
+	_ = parser.AddCommand(rm)
+	_ = parser.AddCommand(add)
+	_ = parser.AddAlias("a", "add")
+
+	want := Schema{
+		Version: SchemaVersion,
+		Commands: []CommandSchema{
+			{
+				Name:        "add",
+				Description: "Add a task",
+				Category:    "Tasks",
+				Arguments: []ArgumentSchema{
+					{Name: "title", Type: "string", Description: "title of the task"},
+					{Name: "priority", Type: "int", Default: 3, Rules: []string{"min(1)"}},
+				},
+				Options: []OptionSchema{{Name: "--project", Type: "string"}},
+			},
+			{
+				Name:       "rm",
+				Deprecated: &DeprecationSchema{Replacement: "remove"},
+				Arguments:  []ArgumentSchema{},
+				Options:    []OptionSchema{},
+			},
+		},
+		Options: []OptionSchema{
+			{Name: "--verbose", Short: "-v", Type: "int", Flag: true, Count: true, Default: 0},
+			{Name: "--color", Type: "bool", Flag: true, Negatable: true, Default: false,
+				Deprecated: &DeprecationSchema{Replacement: "--style"}},
+		},
+		Aliases: []AliasSchema{{Name: "a", Expansion: "add"}},
+	}
+	if got := parser.Schema(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parser.Schema() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewSchemaCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &stdout, Err: &bytes.Buffer{}})
+	_ = parser.AddCommand(NewCommand("list", nil))
+	_ = parser.AddCommand(NewSchemaCommand(parser))
+
+	if _, err := parser.Execute([]string{"__schema"}); err != nil {
+		t.Fatalf("Parser.Execute() error = %v", err)
+	}
+	var got Schema
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("cannot decode schema: %v\n%s", err, stdout.String())
+	}
+	if got.Version != SchemaVersion || len(got.Commands) != 2 || got.Commands[0].Name != "__schema" || !got.Commands[0].Hidden {
+		t.Errorf("schema = %+v, want version %d with the hidden __schema and list commands", got, SchemaVersion)
+	}
+	if help := parser.Help(); strings.Contains(help, "__schema") {
+		t.Errorf("Parser.Help() = %q, want no __schema command", help)
+	}
+}
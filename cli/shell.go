@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"rabbit-todo/cli/param"
+	"strings"
+)
+
+const (
+	shellPrompt      = "rabbit> "
+	maxShellHistory  = 1000
+	historyFileMode  = 0o600
+	shellCommandName = "shell"
+)
+
+// NewShellCommand constructs the "shell" command, which runs an interactive shell
+// that executes command lines with the parser until the input ends or "exit" is entered.
+// The history of the shell is kept in the file at historyPath unless it is empty.
+// The shell itself is never paged.
+func NewShellCommand(p *Parser, historyPath string) Command {
+	command := NewStreamCommand(shellCommandName, func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		return RunShell(p, stdio, historyPath)
+	})
+	command.noPager = true
+	return command
+}
+
+// RunShell reads command lines from stdio.In, splits them with Tokenize and executes them with the parser.
+// The commands run with stdio, so the output of a command is written to stdio.Out and errors are reported on stdio.Err without ending the shell.
+// When stdio.In is an interactive terminal, lines can be edited, recalled from the history
+// and completed with the names of commands and options.
+// It returns when the input ends or the line "exit" is entered.
+func RunShell(p *Parser, stdio *IO, historyPath string) error {
+	history, err := readHistory(historyPath)
+	if err != nil {
+		return err
+	}
+	editor := &lineEditor{
+		in:       bufio.NewReader(stdio.In),
+		out:      stdio.Err,
+		history:  history,
+		complete: p.Complete,
+	}
+
+	// Each command line is paged on its own, even though the shell runs nested in a command
+	lineIO := *stdio
+	lineIO.nested = false
+	for {
+		line, err := readShellLine(editor, stdio)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		tokens, err := Tokenize(line)
+		if err != nil {
+			_, _ = fmt.Fprintln(stdio.Err, err)
+			continue
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		if err := editor.addHistory(line, historyPath); err != nil {
+			return err
+		}
+		if tokens[0] == "exit" {
+			return nil
+		}
+		if isShellCommand(p.Parse(tokens).Command) {
+			_, _ = fmt.Fprintln(stdio.Err, Translate("already in shell"))
+			continue
+		}
+
+		output, err := p.execute(tokens, &lineIO)
+		if err != nil {
+			_, _ = fmt.Fprintln(stdio.Err, err)
+			continue
+		}
+		if output != "" {
+			if _, err := fmt.Fprintln(stdio.Out, output); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// isShellCommand reports whether the command is the shell command,
+// which a command line in the shell must not resolve to, even through an alias.
+func isShellCommand(command *Command) bool {
+	return command != nil && command.Name == shellCommandName
+}
+
+// readShellLine reads a line with the editor, switching the terminal to raw mode while reading
+// if stdio.In is an interactive terminal that supports it. The prompt is only shown on a terminal.
+func readShellLine(editor *lineEditor, stdio *IO) (string, error) {
+	if file, ok := stdio.In.(*os.File); ok && stdio.Interactive {
+		if restore, err := makeRaw(file); err == nil {
+			defer restore()
+			editor.raw = true
+			defer func() { editor.raw = false }()
+		}
+	}
+	if !stdio.Interactive {
+		return editor.readLine("")
+	}
+	return editor.readLine(shellPrompt)
+}
+
+// addHistory appends the line to the history of the editor and to the history file,
+// unless it repeats the previous line.
+func (e *lineEditor) addHistory(line string, historyPath string) error {
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return nil
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxShellHistory {
+		e.history = e.history[len(e.history)-maxShellHistory:]
+	}
+	if historyPath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, historyFileMode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintln(file, line)
+	return err
+}
+
+// readHistory reads the last lines of the history file.
+// A missing file or an empty path is treated as an empty history.
+func readHistory(historyPath string) ([]string, error) {
+	if historyPath == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(historyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil, nil
+	}
+	if len(lines) > maxShellHistory {
+		lines = lines[len(lines)-maxShellHistory:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"rabbit-todo/cli/param"
+	"strings"
+	"testing"
+)
+
+func TestRunShell(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(historyPath, []byte("list\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testAction := func(args map[string]param.Value, opts map[string]param.Value) (string, error) {
+		return "added " + args["title"].StringVal, nil
+	}
+	titleArg, _ := param.NewArgument("title", param.STRING)
+	command := NewCommand("add", testAction)
+	_ = command.AddArgument(titleArg)
+
+	var parserOut, stdout, stderr bytes.Buffer
+	parser := NewParser()
+	parser.SetIO(&IO{In: strings.NewReader(""), Out: &parserOut, Err: &parserOut})
+	_ = parser.AddCommand(command)
+	_ = parser.AddCommand(NewStreamCommand("streamed", func(stdio *IO, args map[string]param.Value, opts map[string]param.Value) error {
+		_, err := fmt.Fprintln(stdio.Out, "streamed")
+		return err
+	}))
+	_ = parser.AddCommand(NewShellCommand(parser, historyPath))
+	quietOption, _ := param.NewFlagOption("--quiet")
+	_ = parser.AddOption(quietOption)
+	_ = parser.AddAlias("again", "shell")
+
+	stdin := strings.Join([]string{
+		`add "Buy milk"`,
+		"",
+		"add 'unterminated",
+		"add",
+		"shell",
+		"--quiet shell",
+		"again",
+		`add Call\ mom   # comment`,
+		`add Call\ mom`,
+		"streamed",
+	}, "\n")
+	err := RunShell(parser, &IO{In: strings.NewReader(stdin), Out: &stdout, Err: &stderr}, historyPath)
+	if err != nil {
+		t.Fatalf("RunShell() error = %v", err)
+	}
+
+	if want := "added Buy milk\nadded Call mom\nadded Call mom\nstreamed\n"; stdout.String() != want {
+		t.Errorf("RunShell() stdout = %q, want %q", stdout.String(), want)
+	}
+	if parserOut.Len() != 0 {
+		t.Errorf("parser output = %q, want nothing", parserOut.String())
+	}
+	wantStderr := "unterminated single quote\n" +
+		"not enough arguments: actual 0, expected 1\n" +
+		"already in shell\n" +
+		"already in shell\n" +
+		"already in shell\n"
+	if stderr.String() != wantStderr {
+		t.Errorf("RunShell() stderr = %q, want %q", stderr.String(), wantStderr)
+	}
+
+	history, err := readHistory(historyPath)
+	if err != nil {
+		t.Fatalf("readHistory() error = %v", err)
+	}
+	wantHistory := []string{"list", `add "Buy milk"`, "add", "shell", "--quiet shell", "again", `add Call\ mom   # comment`, `add Call\ mom`, "streamed"}
+	if strings.Join(history, "\n") != strings.Join(wantHistory, "\n") {
+		t.Errorf("readHistory() = %q, want %q", history, wantHistory)
+	}
+}
+
+func TestRunShell_Exit(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	parser := NewParser()
+	stdin := "exit\nunknown\n"
+	if err := RunShell(parser, &IO{In: strings.NewReader(stdin), Out: &stdout, Err: &stderr}, ""); err != nil {
+		t.Fatalf("RunShell() error = %v", err)
+	}
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("RunShell() output = %q %q, want nothing after exit", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"rabbit-todo/cli/param"
+	"strings"
+)
+
+// expandShortOptions replaces the short forms of the options in params with their long names.
+// A single short form such as `-p` may name any option, while a combined form such as `-vv`
+// is only expanded if every letter is the short form of a flag option.
+// The value that follows an option taking a value is left as it is, even if it looks like a short form.
+func expandShortOptions(options []*param.Option, params []string) []string {
+	expanded := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		names := expandShortOption(options, params[i])
+		expanded = append(expanded, names...)
+
+		option, _ := lookupOption(options, names[len(names)-1])
+		if option != nil && !option.IsFlag && i+1 < len(params) {
+			i++
+			expanded = append(expanded, params[i])
+		}
+	}
+	return expanded
+}
+
+// expandShortOption returns the long names of the options in a short form token,
+// or the token itself if it is not made up of the short forms of the options.
+func expandShortOption(options []*param.Option, token string) []string {
+	if names, ok := shortOptionNames(options, token); ok {
+		return names
+	}
+	return []string{token}
+}
+
+// commandOptionLength returns the number of tokens that the option of the command at args[i] takes up,
+// which is two for an option followed by its value, or 0 if args[i] is not an option of the command.
+func commandOptionLength(command *Command, args []string, i int) int {
+	names := expandShortOption(command.options, args[i])
+	option, _ := lookupOption(command.options, names[len(names)-1])
+	switch {
+	case option == nil:
+		return 0
+	case !option.IsFlag && i+1 < len(args) && isArgument(args[i+1]):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// shortOptionNames returns the long names of the options in a short form token such as `-v` or `-vq`.
+// It reports false if the token is not made up of the short forms of the options.
+func shortOptionNames(options []*param.Option, token string) ([]string, bool) {
+	if len(token) < 2 || token[0] != '-' || strings.HasPrefix(token, optionPrefix) {
+		return nil, false
+	}
+	if option := findShortOption(options, token); option != nil {
+		return []string{option.Name}, true
+	}
+
+	names := make([]string, 0, len(token)-1)
+	for _, letter := range token[1:] {
+		option := findShortOption(options, "-"+string(letter))
+		if option == nil || !option.IsFlag {
+			return nil, false
+		}
+		names = append(names, option.Name)
+	}
+	return names, true
+}
+
+// findShortOption returns the option with the given short form, or nil if there is none.
+func findShortOption(options []*param.Option, short string) *param.Option {
+	for _, option := range options {
+		if option.Short != "" && option.Short == short {
+			return option
+		}
+	}
+	return nil
+}
+
+// flagDefault returns the value of a flag option that is not given:
+// zero for a counting option and false for any other flag.
+func flagDefault(option *param.Option) *param.Value {
+	if option.IsCount {
+		return param.NewIntegerParameterPtr(0)
+	}
+	return param.NewBoolParameterPtr(false)
+}
@@ -0,0 +1,131 @@
+// Package style renders text with ANSI colors and attributes when the output is a terminal that wants them,
+// and as plain text otherwise.
+//
+// Whether styles are rendered depends on the color mode, such as the value of a `--color` option,
+// and the environment: the NO_COLOR variable turns them off and CLICOLOR_FORCE turns them on
+// unless the mode is Always or Never.
+package style
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Mode selects when styles are rendered.
+type Mode int
+
+const (
+	// Auto renders styles when the output is a terminal, unless the environment says otherwise.
+	Auto Mode = iota
+	// Always renders styles regardless of the output and the environment.
+	Always
+	// Never renders plain text.
+	Never
+)
+
+// Modes are the names of the modes as accepted by ParseMode.
+var Modes = []string{"auto", "always", "never"}
+
+// ParseMode returns the mode with the given name: "auto", "always" or "never".
+func ParseMode(name string) (Mode, error) {
+	for i, mode := range Modes {
+		if name == mode {
+			return Mode(i), nil
+		}
+	}
+	return Auto, fmt.Errorf("invalid color mode %s", name)
+}
+
+func (m Mode) String() string {
+	if m < 0 || int(m) >= len(Modes) {
+		return "unknown"
+	}
+	return Modes[m]
+}
+
+// Style is a combination of ANSI colors and attributes.
+type Style struct {
+	codes []string
+}
+
+var (
+	Bold      = Style{codes: []string{"1"}}
+	Dim       = Style{codes: []string{"2"}}
+	Underline = Style{codes: []string{"4"}}
+	Red       = Style{codes: []string{"31"}}
+	Green     = Style{codes: []string{"32"}}
+	Yellow    = Style{codes: []string{"33"}}
+	Blue      = Style{codes: []string{"34"}}
+	Magenta   = Style{codes: []string{"35"}}
+	Cyan      = Style{codes: []string{"36"}}
+)
+
+// With returns a style that combines the style with the others, as in Bold.With(Red).
+func (s Style) With(others ...Style) Style {
+	codes := append([]string{}, s.codes...)
+	for _, other := range others {
+		codes = append(codes, other.codes...)
+	}
+	return Style{codes: codes}
+}
+
+// Styler renders styled text for one output.
+type Styler struct {
+	enabled bool
+}
+
+// Plain is a Styler that never renders styles.
+var Plain = &Styler{}
+
+// New constructs a Styler for the output w in the given mode.
+// In the Auto mode, styles are rendered if CLICOLOR_FORCE is set to a value other than "0",
+// or if NO_COLOR is not set and w is a terminal whose TERM is not "dumb".
+func New(w io.Writer, mode Mode) *Styler {
+	return &Styler{enabled: enabled(w, mode)}
+}
+
+func enabled(w io.Writer, mode Mode) bool {
+	switch mode {
+	case Always:
+		return true
+	case Never:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	file, ok := w.(*os.File)
+	return ok && isTerminal(file) && os.Getenv("TERM") != "dumb"
+}
+
+// Enabled reports whether the Styler renders styles.
+func (s *Styler) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// Render returns the text wrapped in the ANSI codes of the style, or the text itself if styles are not rendered.
+func (s *Styler) Render(style Style, text string) string {
+	if !s.Enabled() || len(style.codes) == 0 || text == "" {
+		return text
+	}
+	return "\x1b[" + strings.Join(style.codes, ";") + "m" + text + "\x1b[0m"
+}
+
+// Sprintf formats according to the format and renders the result in the style.
+func (s *Styler) Sprintf(style Style, format string, args ...interface{}) string {
+	return s.Render(style, fmt.Sprintf(format, args...))
+}
+
+// isTerminal reports whether the file is a character device such as a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
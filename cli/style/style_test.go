@@ -0,0 +1,73 @@
+package style
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	type testCase struct {
+		testName   string
+		input      string
+		want       Mode
+		wantErrStr string
+	}
+	tests := []testCase{
+		{testName: "Ok-Auto", input: "auto", want: Auto},
+		{testName: "Ok-Always", input: "always", want: Always},
+		{testName: "Ok-Never", input: "never", want: Never},
+		{testName: "Error-Unknown", input: "sometimes", wantErrStr: "invalid color mode sometimes"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := ParseMode(tc.input)
+			if tc.wantErrStr != "" {
+				if err == nil || err.Error() != tc.wantErrStr {
+					t.Errorf("ParseMode() error = %v, wantErrStr %q", err, tc.wantErrStr)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("ParseMode() = %v, %v, want %v", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestStyler_Render(t *testing.T) {
+	type testCase struct {
+		testName      string
+		mode          Mode
+		noColor       string
+		cliColorForce string
+		style         Style
+		want          string
+	}
+	tests := []testCase{
+		{testName: "Ok-AutoNotTerminal", mode: Auto, style: Red, want: "overdue"},
+		{testName: "Ok-Always", mode: Always, style: Red, want: "\x1b[31moverdue\x1b[0m"},
+		{testName: "Ok-Combined", mode: Always, style: Bold.With(Red), want: "\x1b[1;31moverdue\x1b[0m"},
+		{testName: "Ok-AlwaysOverNoColor", mode: Always, noColor: "1", style: Red, want: "\x1b[31moverdue\x1b[0m"},
+		{testName: "Ok-Never", mode: Never, cliColorForce: "1", style: Red, want: "overdue"},
+		{testName: "Ok-CliColorForce", mode: Auto, cliColorForce: "1", style: Red, want: "\x1b[31moverdue\x1b[0m"},
+		{testName: "Ok-CliColorForceZero", mode: Auto, cliColorForce: "0", style: Red, want: "overdue"},
+		{testName: "Ok-NoColorOverForce", mode: Auto, noColor: "1", cliColorForce: "1", style: Red, want: "overdue"},
+		{testName: "Ok-EmptyStyle", mode: Always, style: Style{}, want: "overdue"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("CLICOLOR_FORCE", tc.cliColorForce)
+			styler := New(&bytes.Buffer{}, tc.mode)
+			if got := styler.Render(tc.style, "overdue"); got != tc.want {
+				t.Errorf("Styler.Render() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Ok-Plain", func(t *testing.T) {
+		if got := Plain.Sprintf(Bold, "%d tasks", 3); got != "3 tasks" {
+			t.Errorf("Styler.Sprintf() = %q, want %q", got, "3 tasks")
+		}
+	})
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw puts the terminal of the file into raw mode, so that key presses are read one at a time
+// without echo. It returns a function that restores the previous mode.
+func makeRaw(file *os.File) (func(), error) {
+	fd := file.Fd()
+	var old syscall.Termios
+	if err := ioctlTermios(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.INLCR | syscall.IGNCR | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = ioctlTermios(fd, syscall.TCSETS, &old)
+	}, nil
+}
+
+func ioctlTermios(fd uintptr, request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// terminalSize returns the number of columns and rows of the terminal of the file.
+func terminalSize(file *os.File) (int, int, error) {
+	var size struct{ rows, cols, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(size.cols), int(size.rows), nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// makeRaw is not supported on this platform, so the shell falls back to reading whole lines.
+func makeRaw(file *os.File) (func(), error) {
+	return nil, fmt.Errorf("raw terminal mode is not supported")
+}
+
+// terminalSize is not supported on this platform, so the default width and height are used.
+func terminalSize(file *os.File) (int, int, error) {
+	return 0, 0, fmt.Errorf("terminal size is not supported")
+}
@@ -0,0 +1,80 @@
+package cli
+
+import "strings"
+
+// Tokenize splits a string into command-line tokens using POSIX shell-like rules.
+// Tokens are separated by unquoted whitespace, including newlines.
+// Single quotes preserve every character up to the closing quote,
+// double quotes preserve every character except for the escapes \" \\ \$ and \`,
+// and a backslash outside of quotes escapes the next character.
+// An unquoted '#' at the start of a token begins a comment that runs to the end of the line.
+// It returns an error if a quote is not terminated or the string ends with a backslash.
+func Tokenize(s string) ([]string, error) {
+	tokens := make([]string, 0)
+	var builder strings.Builder
+	inToken := false
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				tokens = append(tokens, builder.String())
+				builder.Reset()
+				inToken = false
+			}
+		case r == '#' && !inToken:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, Errorf("trailing backslash")
+			}
+			i++
+			// A backslash-newline continues the line
+			if runes[i] != '\n' {
+				builder.WriteRune(runes[i])
+				inToken = true
+			}
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, Errorf("unterminated single quote")
+			}
+			builder.WriteString(string(runes[i+1 : end]))
+			i = end
+			inToken = true
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				builder.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, Errorf("unterminated double quote")
+			}
+			inToken = true
+		default:
+			builder.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inToken {
+		tokens = append(tokens, builder.String())
+	}
+	return tokens, nil
+}
+
+// indexRune returns the index of the first r in runes at or after start, or -1 if there is none.
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
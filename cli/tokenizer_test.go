@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	type testCase struct {
+		testName   string
+		input      string
+		want       []string
+		wantErr    bool
+		wantErrStr string
+	}
+	tests := []testCase{
+		{
+			testName: "Ok-Whitespace",
+			input:    "  add\tBuy milk \n --priority  3 ",
+			want:     []string{"add", "Buy", "milk", "--priority", "3"},
+		},
+		{
+			testName: "Ok-Empty",
+			input:    " \n ",
+			want:     []string{},
+		},
+		{
+			testName: "Ok-SingleQuote",
+			input:    `add 'Buy "milk" \n'`,
+			want:     []string{"add", `Buy "milk" \n`},
+		},
+		{
+			testName: "Ok-DoubleQuote",
+			input:    `add "Buy \"milk\" \n \\ \$"`,
+			want:     []string{"add", `Buy "milk" \n \ $`},
+		},
+		{
+			testName: "Ok-EmptyQuotes",
+			input:    `add '' ""`,
+			want:     []string{"add", "", ""},
+		},
+		{
+			testName: "Ok-AdjacentQuotes",
+			input:    `--title=Buy' 'milk"!"`,
+			want:     []string{"--title=Buy milk!"},
+		},
+		{
+			testName: "Ok-Backslash",
+			input:    "add Buy\\ milk \\\n--done",
+			want:     []string{"add", "Buy milk", "--done"},
+		},
+		{
+			testName: "Ok-Comment",
+			input:    "# tasks for today\nadd milk#2 # trailing comment\nlist",
+			want:     []string{"add", "milk#2", "list"},
+		},
+		{
+			testName:   "Error-UnterminatedSingleQuote",
+			input:      "add 'Buy milk",
+			wantErr:    true,
+			wantErrStr: "unterminated single quote",
+		},
+		{
+			testName:   "Error-UnterminatedDoubleQuote",
+			input:      `add "Buy milk\"`,
+			wantErr:    true,
+			wantErrStr: "unterminated double quote",
+		},
+		{
+			testName:   "Error-TrailingBackslash",
+			input:      `add milk\`,
+			wantErr:    true,
+			wantErrStr: "trailing backslash",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			got, err := Tokenize(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Tokenize() error = %v, wantError %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if err.Error() != tc.wantErrStr {
+					t.Errorf("Tokenize() error = %q, wantErrStr %q", err, tc.wantErrStr)
+				}
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Tokenize() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}